@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -99,6 +102,16 @@ func (r *Reader) readLine() {
 	}
 }
 
+// decodeLine decodes the current line with dec and stores it to out.
+// The line number is filled in ValidationError returned from dec.
+func (r *Reader) decodeLine(dec rowDecoder, out reflect.Value) error {
+	err := dec.decode(r.cur, out)
+	if ve, ok := err.(*ValidationError); ok {
+		ve.Line = r.lineno
+	}
+	return err
+}
+
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
 
 // Loop reads from r until an error or EOF and invokes body everytime it reads a line.
@@ -179,7 +192,7 @@ func (r *Reader) Loop(body interface{}) (err error) {
 			break
 		}
 		p := reflect.New(inStruct)
-		if err := dec.decode(r.cur, p); err != nil {
+		if err := r.decodeLine(dec, p); err != nil {
 			r.err = err
 			break
 		}
@@ -258,7 +271,7 @@ func (r *Reader) Read(e interface{}) bool {
 		return false
 	}
 	// TODO: Append the line number to the error message.
-	r.err = decoder.decode(r.cur, reflect.ValueOf(e))
+	r.err = r.decodeLine(decoder, reflect.ValueOf(e))
 	return r.err == nil
 }
 
@@ -305,7 +318,7 @@ func (r *Reader) ReadAll(s interface{}) (err error) {
 		}
 		p := reflect.New(et)
 		v := reflect.ValueOf(s).Elem()
-		err := decoder.decode(r.cur, p)
+		err := r.decodeLine(decoder, p)
 		v.Set(reflect.Append(v, p.Elem()))
 		if err != nil {
 			r.err = err
@@ -364,6 +377,14 @@ type rowDecoder interface {
 	consumeHeader([]string) error
 }
 
+// isConverterTo returns true if conv is a valid converter to t.
+func isConverterTo(conv interface{}, t reflect.Type) bool {
+	convType := reflect.TypeOf(conv)
+	return convType != nil && convType.Kind() == reflect.Func &&
+		convType.NumIn() == 1 && convType.In(0).Kind() == reflect.String &&
+		convType.NumOut() == 2 && convType.Out(0) == t && convType.Out(1) == errorType
+}
+
 func validateCustomConverter(conv interface{}, enc string, field reflect.StructField, errs *[]string) bool {
 	convType := reflect.TypeOf(conv)
 	if convType.Kind() != reflect.Func {
@@ -395,44 +416,235 @@ func validateCustomConverter(conv interface{}, enc string, field reflect.StructF
 	return ok
 }
 
-func parseStructTag(
-	opt Option,
-	field reflect.StructField,
-	fieldIdx int,
-	nameMap map[string]int,
-	idxMap map[int]int,
-	converters *[]reflect.Value,
-	errors *[]string) {
+// fieldOption returns a copy of opt in which options are overridden by the struct tag of field.
+func fieldOption(opt Option, field reflect.StructField) Option {
+	if layout := field.Tag.Get("layout"); layout != "" {
+		opt.TimeLayout = layout
+	}
+	if t := field.Tag.Get("true"); t != "" {
+		opt.TrueValues = strings.Split(t, ",")
+	}
+	if f := field.Tag.Get("false"); f != "" {
+		opt.FalseValues = strings.Split(f, ",")
+	}
+	if sep := field.Tag.Get("thousands"); sep != "" {
+		opt.ThousandsSeparator = sep
+	}
+	if sep := field.Tag.Get("decimal"); sep != "" {
+		opt.DecimalSeparator = sep
+	}
+	if trim, ok := field.Tag.Lookup("trim"); ok {
+		opt.TrimSpace = trim == "true"
+	}
+	if collapse, ok := field.Tag.Lookup("collapse"); ok {
+		opt.CollapseSpace = collapse == "true"
+	}
+	if c := field.Tag.Get("case"); c != "" {
+		opt.Case = c
+	}
+	return opt
+}
+
+// structMapping is the mapping from columns in CSV to fields in a struct.
+// Fields in embedded structs are flattened into the mapping.
+type structMapping struct {
+	// names and indice map column names and column indice to fields.
+	names  map[string]int
+	indice map[int]int
+	// converters, fields and fieldNames store the converters, the index sequences
+	// (see reflect.Value.FieldByIndex) and the names of fields.
+	converters []reflect.Value
+	fields     [][]int
+	fieldNames []string
+	// transforms are the funcs applied to values before they are converted (see Option.textTransformer).
+	transforms []func(string) string
+	// defaults are the default values of fields specified by default tags.
+	defaults map[int]string
+	// required are the fields with required:"true" tags.
+	required map[int]bool
+	// groups are the fields to which multiple columns are mapped.
+	groups []*columnGroup
+	// numFields is the number of the fields parsed so far, which is used for AutoIndex.
+	numFields int
+	// visiting is the set of the struct types on the path from the root struct to the struct being parsed.
+	visiting map[reflect.Type]bool
+	errors   []string
+}
+
+func newStructMapping() *structMapping {
+	return &structMapping{
+		names:    make(map[string]int),
+		indice:   make(map[int]int),
+		defaults: make(map[int]string),
+		required: make(map[int]bool),
+		visiting: make(map[reflect.Type]bool),
+	}
+}
+
+// nestedStruct returns the struct type of field and the prefix of the column names of its fields
+// if field is a struct (or a pointer to a struct) whose fields are mapped to columns.
+// Fields of embedded structs and structs with inline tag are flattened without prefix.
+// Fields of structs with prefix tag are mapped to columns with the prefix.
+// Fields of structs with name tag are mapped to columns with the dotted names (e.g. "address.city")
+// unless converters for the structs are available.
+func nestedStruct(opt Option, field reflect.StructField) (reflect.Type, string, bool) {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, "", false
+	}
 	tag := field.Tag
-	name := tag.Get("name")
-	index := tag.Get("index")
-	if name == "" && index == "" {
-		*errors = append(*errors, fmt.Sprintf("Please specify name or index to the struct field: %s", field.Name))
-		return
+	if prefix, ok := tag.Lookup("prefix"); ok {
+		return t, prefix, true
 	}
-	if name != "" && index != "" {
-		*errors = append(*errors, fmt.Sprintf("Please specify name or index to the struct field: %s", field.Name))
-		return
+	if tag.Get("inline") == "true" {
+		return t, "", true
+	}
+	name, index := tag.Get("name"), tag.Get("index")
+	if field.Anonymous && name == "" && index == "" {
+		return t, "", true
+	}
+	if name == "" && index == "" && opt.AutoName {
+		name = opt.columnName(field.Name)
+	}
+	if name == "-" {
+		return nil, "", false
+	}
+	if name != "" && tag.Get("enc") == "" {
+		if conv, err := createConverterFromType(opt, field.Type); conv == nil && err == nil {
+			return t, name + ".", true
+		}
+	}
+	return nil, "", false
+}
+
+// parseStruct parses struct tags of the fields in t and adds them to m.
+// path and prefix are the index sequence and the name of t in the root struct.
+// namePrefix is prepended to the names of columns.
+func (m *structMapping) parseStruct(opt Option, t reflect.Type, path []int, prefix, namePrefix string) {
+	m.visiting[t] = true
+	defer delete(m.visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fpath := append(append([]int(nil), path...), i)
+		if ft, p, ok := nestedStruct(opt, f); ok && f.Tag.Get("sep") == "" {
+			if m.visiting[ft] {
+				m.errors = append(m.errors, fmt.Sprintf("Field %s has a recursive struct type %v", prefix+f.Name, f.Type))
+				continue
+			}
+			m.parseStruct(opt, ft, fpath, prefix+f.Name+".", namePrefix+p)
+			continue
+		}
+		m.parseStructTag(opt, f, fpath, prefix+f.Name, namePrefix)
+	}
+}
+
+// createFieldConverter creates a converter for field based on the type and the struct tag of field.
+func (m *structMapping) createFieldConverter(opt Option, field reflect.StructField, fieldName string) interface{} {
+	if sep := field.Tag.Get("sep"); sep != "" {
+		if t := field.Type; t.Kind() == reflect.Struct || t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+			return m.createCompoundConverter(opt, field, fieldName)
+		}
+		if field.Type.Kind() != reflect.Slice {
+			m.errors = append(m.errors, fmt.Sprintf("sep is specified to field %s, but the type is %v", fieldName, field.Type))
+			return nil
+		}
+		elem := field
+		elem.Type = field.Type.Elem()
+		conv := m.createTypeConverter(opt, elem, fieldName)
+		if conv == nil {
+			return nil
+		}
+		return createSplitConverter(field.Type, sep, preprocessConverter(conv, opt.textTransformer()))
+	}
+	return m.createTypeConverter(opt, field, fieldName)
+}
+
+// createCompoundConverter creates a converter for a struct field whose fields are packed into a single value
+// with the separator specified by sep tag (e.g. "35.6,139.7"). The components are mapped to the fields in order.
+// If kv tag is specified, each component is a pair of a key and a value separated by kv (e.g. "w=10;h=20")
+// and the keys are mapped to the fields by name tags or the field names (case-insensitive).
+// Unexported fields are excluded from the components. TrimSpace, CollapseSpace and Case are applied to each component.
+func (m *structMapping) createCompoundConverter(opt Option, field reflect.StructField, fieldName string) interface{} {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var fields []compoundField
+	ok := true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		fopt := fieldOption(opt, f)
+		if err := fopt.validateCase(); err != nil {
+			m.errors = append(m.errors, fmt.Sprintf("Invalid case tag of field %s: %q", fieldName+"."+f.Name, fopt.Case))
+			ok = false
+			continue
+		}
+		conv := m.createFieldConverter(fopt, f, fieldName+"."+f.Name)
+		if conv == nil {
+			ok = false
+			continue
+		}
+		conv, _, valid := m.addValidator(fopt, f, conv, fieldName+"."+f.Name)
+		if !valid {
+			ok = false
+			continue
+		}
+		conv = preprocessConverter(conv, fopt.textTransformer())
+		key := f.Tag.Get("name")
+		if key == "" {
+			key = f.Name
+		}
+		fields = append(fields, compoundField{index: i, key: key, converter: reflect.ValueOf(conv)})
+	}
+	if !ok {
+		return nil
+	}
+	conv := createCompoundConverter(t, field.Tag.Get("sep"), field.Tag.Get("kv"), fields)
+	if field.Type.Kind() == reflect.Ptr {
+		return createPtrConverter(opt, field.Type, conv)
+	}
+	return conv
+}
+
+// createTypeConverter creates a converter to field.Type with the encoding specified by enc tag.
+func (m *structMapping) createTypeConverter(opt Option, field reflect.StructField, fieldName string) interface{} {
+	if err := opt.validateSeparators(); err != nil {
+		m.errors = append(m.errors, fmt.Sprintf("Invalid separators of field %s: %v", fieldName, err))
+		return nil
 	}
 	var conv interface{}
-	enc := tag.Get("enc")
+	enc := field.Tag.Get("enc")
 	if enc != "" {
 		if opt.Decoders != nil && opt.Decoders[enc] != nil {
 			conv = opt.Decoders[enc]
-			if !validateCustomConverter(conv, enc, field, errors) {
+			if field.Type.Kind() == reflect.Ptr && isConverterTo(conv, field.Type.Elem()) {
+				conv = createPtrConverter(opt, field.Type, conv)
+			}
+			if !validateCustomConverter(conv, enc, field, &m.errors) {
 				conv = nil
 			}
 		} else {
 			pre := predefinedDecoders[enc]
 			// TODO: Test these errors.
 			if pre != nil {
-				conv = pre(field.Type)
+				conv = pre(opt, field.Type)
+				if conv == nil && field.Type.Kind() == reflect.Ptr {
+					if c := pre(opt, field.Type.Elem()); c != nil {
+						conv = createPtrConverter(opt, field.Type, c)
+					}
+				}
 				if conv == nil {
-					*errors = append(*errors, fmt.Sprintf("Encoding %q does not support %v", enc, field.Type))
+					m.errors = append(m.errors, fmt.Sprintf("Encoding %q does not support %v", enc, field.Type))
 				}
 			} else {
-				*errors = append(*errors, fmt.Sprintf("Encoding %q is not defined", enc))
-				return
+				m.errors = append(m.errors, fmt.Sprintf("Encoding %q is not defined", enc))
+				return nil
 			}
 		}
 	}
@@ -440,24 +652,299 @@ func parseStructTag(
 		var err error
 		conv, err = createConverterFromType(opt, field.Type)
 		if err != nil {
-			*errors = append(*errors, err.Error())
+			m.errors = append(m.errors, err.Error())
+		}
+	}
+	if conv == nil {
+		m.errors = append(m.errors, fmt.Sprintf("Unexpected field type for %s: %s", fieldName, field.Type))
+		return nil
+	}
+	return createNullConverter(opt, field.Type, conv, fieldName)
+}
+
+// columnGroup is a field to which multiple columns are mapped.
+// The field must be a slice or a map with string keys.
+type columnGroup struct {
+	// field is the index of the field in structMapping.
+	field int
+	// from and to are the range of column indice (inclusive). to is negative if the range is open-ended.
+	from, to int
+	// match reports whether a column is mapped to the field by the column name.
+	match func(string) bool
+	// rest is true if the columns which are not mapped to other fields are mapped to the field.
+	rest bool
+	// columns and keys are the indice and the names of the columns found in the header.
+	columns []int
+	keys    []string
+}
+
+func (g *columnGroup) needHeader() bool {
+	return g.match != nil || g.rest
+}
+
+func (g *columnGroup) addColumn(i int, name string) {
+	g.columns = append(g.columns, i)
+	g.keys = append(g.keys, name)
+}
+
+// parseIndexRange parses a range of column indice like "3-7" or "5...".
+func parseIndexRange(index string) (from, to int, err error) {
+	if strings.HasSuffix(index, "...") {
+		from, err = strconv.Atoi(strings.TrimSuffix(index, "..."))
+		if err == nil && from < 0 {
+			err = fmt.Errorf("negative index: %d", from)
+		}
+		return from, -1, err
+	}
+	r := strings.SplitN(index, "-", 2)
+	if len(r) != 2 {
+		return 0, 0, fmt.Errorf("invalid range: %q", index)
+	}
+	if from, err = strconv.Atoi(r[0]); err != nil {
+		return 0, 0, err
+	}
+	if to, err = strconv.Atoi(r[1]); err != nil {
+		return 0, 0, err
+	}
+	if from < 0 || to < from {
+		return 0, 0, fmt.Errorf("invalid range: %q", index)
+	}
+	return from, to, nil
+}
+
+// compileNamePattern returns a func to match column names with a glob pattern or a regexp enclosed by "/".
+// Only column names starting with prefix are matched with the pattern excluding prefix.
+func compileNamePattern(prefix, pattern string) (func(string) bool, error) {
+	var match func(string) bool
+	if len(pattern) > 2 && pattern[0] == '/' && pattern[len(pattern)-1] == '/' {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	} else {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+		match = func(s string) bool {
+			ok, _ := path.Match(pattern, s)
+			return ok
+		}
+	}
+	return func(s string) bool {
+		return strings.HasPrefix(s, prefix) && match(s[len(prefix):])
+	}, nil
+}
+
+// parseColumnGroup parses field if multiple columns are mapped to field.
+// A range of indice (e.g. index:"3-7" or index:"5..."), a pattern of names (e.g. match:"score_*")
+// or rest:"true" is specified to such a field. name tags are always exact column names.
+// parseColumnGroup returns false if field is not such a field.
+func (m *structMapping) parseColumnGroup(opt Option, field reflect.StructField, path []int, fieldName, namePrefix string) bool {
+	tag := field.Tag
+	name, index, pattern := tag.Get("name"), tag.Get("index"), tag.Get("match")
+	g := &columnGroup{}
+	var err error
+	switch {
+	case pattern != "" && (name != "" || index != ""):
+		m.errors = append(m.errors, fmt.Sprintf("Please specify only one of match, name and index to the struct field: %s", fieldName))
+		return true
+	case tag.Get("rest") == "true":
+		g.rest = true
+	case strings.HasSuffix(index, "...") || strings.Contains(index, "-"):
+		g.from, g.to, err = parseIndexRange(index)
+		if err != nil {
+			m.errors = append(m.errors, fmt.Sprintf("Failed to parse index of field %s: %q", fieldName, index))
+			return true
+		}
+		// Fields after the range are mapped to the columns after the range with AutoIndex.
+		end := g.to
+		if end < 0 {
+			end = g.from
+		}
+		if end >= m.numFields {
+			m.numFields = end + 1
+		}
+	case pattern != "":
+		g.match, err = compileNamePattern(namePrefix, pattern)
+		if err != nil {
+			m.errors = append(m.errors, fmt.Sprintf("Failed to parse match of field %s: %v", fieldName, err))
+			return true
+		}
+	default:
+		return false
+	}
+	t := field.Type
+	if !(t.Kind() == reflect.Slice && !g.rest ||
+		t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && g.needHeader()) {
+		m.errors = append(m.errors, fmt.Sprintf("The type of field %s must be a slice or a map with string keys to map multiple columns, but %v", fieldName, t))
+		return true
+	}
+	unsupported := false
+	for _, key := range []string{"sep", "default"} {
+		if _, ok := tag.Lookup(key); ok {
+			m.errors = append(m.errors, fmt.Sprintf("%s tag is not supported for field %s to which multiple columns are mapped", key, fieldName))
+			unsupported = true
 		}
 	}
+	if unsupported {
+		return true
+	}
+	elem := field
+	elem.Type = t.Elem()
+	conv := m.createTypeConverter(opt, elem, fieldName)
 	if conv == nil {
-		*errors = append(*errors, fmt.Sprintf("Unexpected field type for %s: %s", field.Name, field.Type))
+		return true
+	}
+	// Validation tags are applied to each column.
+	conv, _, ok := m.addValidator(opt, elem, conv, fieldName)
+	if !ok {
+		return true
+	}
+	g.field = m.addField(opt, conv, path, fieldName)
+	m.groups = append(m.groups, g)
+	return true
+}
+
+// contains returns true if the range of g contains the column index i.
+func (g *columnGroup) contains(i int) bool {
+	return !g.needHeader() && i >= g.from && (g.to < 0 || i <= g.to)
+}
+
+// checkIndexOverlaps reports errors if the ranges of indice overlap with each other or with single indice.
+func (m *structMapping) checkIndexOverlaps() {
+	var indice []int
+	for i := range m.indice {
+		indice = append(indice, i)
+	}
+	sort.Ints(indice)
+	for k, g := range m.groups {
+		if g.needHeader() {
+			continue
+		}
+		for _, i := range indice {
+			if g.contains(i) {
+				m.errors = append(m.errors, fmt.Sprintf("Fields %s and %s have the same index %d", m.fieldNames[g.field], m.fieldNames[m.indice[i]], i))
+			}
+		}
+		for _, h := range m.groups[k+1:] {
+			if !h.needHeader() && (g.contains(h.from) || h.contains(g.from)) {
+				m.errors = append(m.errors, fmt.Sprintf("The index ranges of fields %s and %s overlap", m.fieldNames[g.field], m.fieldNames[h.field]))
+			}
+		}
+	}
+}
+
+// sameParent returns true if the fields specified by the index sequences a and b belong to the same struct.
+func sameParent(a, b []int) bool {
+	return reflect.DeepEqual(a[:len(a)-1], b[:len(b)-1])
+}
+
+// addValidator wraps conv, a converter for field, with the validator for the validation tags of field.
+// If field is a slice or a map to which multiple columns are mapped, field.Type must be the element type.
+// It returns false if the tags are invalid.
+func (m *structMapping) addValidator(opt Option, field reflect.StructField, conv interface{}, fieldName string) (interface{}, *fieldValidator, bool) {
+	fv, err := newFieldValidator(field)
+	if err != nil {
+		m.errors = append(m.errors, fmt.Sprintf("Invalid validation tags of field %s: %v", fieldName, err))
+		return nil, nil, false
+	}
+	if fv != nil {
+		conv = createValidatingConverter(opt, conv, fv, fieldName)
+	}
+	return conv, fv, true
+}
+
+// addField adds a field to m and returns the index of the field in m.
+// opt is the option of the field overridden by the struct tag.
+func (m *structMapping) addField(opt Option, conv interface{}, path []int, fieldName string) int {
+	if err := opt.validateCase(); err != nil {
+		m.errors = append(m.errors, fmt.Sprintf("Invalid case tag of field %s: %q", fieldName, opt.Case))
+	}
+	m.converters = append(m.converters, reflect.ValueOf(conv))
+	m.fields = append(m.fields, path)
+	m.fieldNames = append(m.fieldNames, fieldName)
+	m.transforms = append(m.transforms, opt.textTransformer())
+	return len(m.fields) - 1
+}
+
+func (m *structMapping) parseStructTag(opt Option, field reflect.StructField, path []int, fieldName, namePrefix string) {
+	errors := &m.errors
+	tag := field.Tag
+	name := tag.Get("name")
+	index := tag.Get("index")
+	if m.parseColumnGroup(fieldOption(opt, field), field, path, fieldName, namePrefix) {
 		return
 	}
-	*converters = append(*converters, reflect.ValueOf(conv))
-	if name != "" {
-		nameMap[name] = fieldIdx
+	if name == "-" {
+		return
+	}
+	position := m.numFields
+	m.numFields++
+	if name == "" && index == "" {
+		if opt.AutoName {
+			name = opt.columnName(field.Name)
+		} else if opt.AutoIndex {
+			index = strconv.Itoa(position)
+		}
+	}
+	if name == "" && index == "" {
+		*errors = append(*errors, fmt.Sprintf("Please specify name or index to the struct field: %s", fieldName))
+		return
+	}
+	if name != "" && index != "" {
+		*errors = append(*errors, fmt.Sprintf("Please specify name or index to the struct field: %s", fieldName))
+		return
+	}
+	opt = fieldOption(opt, field)
+	conv := m.createFieldConverter(opt, field, fieldName)
+	if conv == nil {
 		return
 	}
-	i, err := strconv.Atoi(index)
-	if err != nil || i < 0 {
-		*errors = append(*errors, fmt.Sprintf("Failed to parse index of field %s: %q", field.Name, index))
+	conv, fv, ok := m.addValidator(opt, field, conv, fieldName)
+	if !ok {
 		return
 	}
-	idxMap[i] = fieldIdx
+	def, hasDefault := tag.Lookup("default")
+	if hasDefault {
+		if _, err := callConverter(reflect.ValueOf(conv), def); err != nil {
+			msg := err.Error()
+			if ve, ok := err.(*ValidationError); ok {
+				msg = ve.Message
+			}
+			*errors = append(*errors, fmt.Sprintf("Failed to decode the default value of field %s: %s", fieldName, msg))
+			return
+		}
+		conv = createDefaultValueConverter(opt, conv, def)
+	}
+	var j int
+	if name != "" {
+		name = namePrefix + name
+		if k, ok := m.names[name]; ok && !sameParent(m.fields[k], path) {
+			*errors = append(*errors, fmt.Sprintf("Fields %s and %s have the same name %q", m.fieldNames[k], fieldName, name))
+			return
+		}
+		j = m.addField(opt, conv, path, fieldName)
+		m.names[name] = j
+	} else {
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 {
+			*errors = append(*errors, fmt.Sprintf("Failed to parse index of field %s: %q", fieldName, index))
+			return
+		}
+		if k, ok := m.indice[i]; ok && !sameParent(m.fields[k], path) {
+			*errors = append(*errors, fmt.Sprintf("Fields %s and %s have the same index %d", m.fieldNames[k], fieldName, i))
+			return
+		}
+		j = m.addField(opt, conv, path, fieldName)
+		m.indice[i] = j
+	}
+	if hasDefault {
+		m.defaults[j] = def
+	}
+	if fv != nil && fv.required {
+		m.required[j] = true
+	}
 }
 
 func newDecoder(opt Option, t reflect.Type) (rowDecoder, error) {
@@ -480,20 +967,31 @@ func newSliceDecoder(opt Option, t reflect.Type) (rowDecoder, error) {
 	}
 	return &sliceRowDecoder{
 		elemType:  elem,
-		converter: reflect.ValueOf(c),
+		converter: reflect.ValueOf(createNullConverter(opt, elem, c, elem.String())),
+		hook:      opt.Transform,
+		transform: opt.textTransformer(),
 	}, nil
 }
 
 type sliceRowDecoder struct {
 	elemType  reflect.Type
 	converter reflect.Value
+	// hook and transform are applied to values before they are converted.
+	hook      func(column, value string) string
+	transform func(string) string
 }
 
 func (d *sliceRowDecoder) needHeader() bool             { return false }
 func (d *sliceRowDecoder) consumeHeader([]string) error { return nil }
 func (d *sliceRowDecoder) decode(s []string, out reflect.Value) error {
 	slicePtr := reflect.New(reflect.SliceOf(d.elemType))
-	for _, e := range s {
+	for i, e := range s {
+		if d.hook != nil {
+			e = d.hook(strconv.Itoa(i), e)
+		}
+		if d.transform != nil {
+			e = d.transform(e)
+		}
 		rets := d.converter.Call([]reflect.Value{reflect.ValueOf(e)})
 		if len(rets) != 2 {
 			panic("converter must return two values.")
@@ -507,48 +1005,74 @@ func (d *sliceRowDecoder) decode(s []string, out reflect.Value) error {
 	return nil
 }
 
+// unexportedFields returns the names of unexported fields in t.
+// Exported fields in unexported embedded structs are settable. So they are not reported.
+// Fields of structs with sep tag are not reported because unexported fields are skipped in such structs.
+func unexportedFields(opt Option, t reflect.Type, prefix string, visiting map[reflect.Type]bool) []string {
+	visiting[t] = true
+	defer delete(visiting, t)
+	var unexported []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if ft, _, ok := nestedStruct(opt, f); ok && f.Tag.Get("sep") == "" && (f.PkgPath == "" || f.Anonymous && f.Type.Kind() == reflect.Struct) {
+			// Recursive structs are reported by parseStruct.
+			if !visiting[ft] {
+				unexported = append(unexported, unexportedFields(opt, ft, prefix+f.Name+".", visiting)...)
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			unexported = append(unexported, prefix+f.Name)
+		}
+	}
+	return unexported
+}
+
 func newStructDecoder(opt Option, t reflect.Type) (rowDecoder, error) {
 	if t.NumField() == 0 {
 		return nil, errors.New("The struct has no field")
 	}
-	v := reflect.New(t).Elem()
-	var unexported []string
-	for i := 0; i < v.NumField(); i++ {
-		if !v.Field(i).CanSet() {
-			unexported = append(unexported, t.Field(i).Name)
-		}
-	}
-	if unexported != nil {
+	if unexported := unexportedFields(opt, t, "", make(map[reflect.Type]bool)); unexported != nil {
 		return nil, fmt.Errorf("The struct passed to Loop must not have unexported fields: %s", strings.Join(unexported, ", "))
 	}
 
-	var tagErrors []string
-	nameMap := make(map[string]int)
-	idxMap := make(map[int]int)
-	var converters []reflect.Value
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		parseStructTag(opt, f, i, nameMap, idxMap, &converters, &tagErrors)
+	m := newStructMapping()
+	m.parseStruct(opt, t, nil, "", "")
+	m.checkIndexOverlaps()
+	nameMap, idxMap, tagErrors := m.names, m.indice, m.errors
+	useNames, useIndice := len(nameMap) != 0, len(idxMap) != 0
+	for _, g := range m.groups {
+		if g.needHeader() {
+			useNames = true
+		} else {
+			useIndice = true
+		}
 	}
-	if len(nameMap) != 0 && len(idxMap) != 0 {
+	if useNames && useIndice {
 		tagErrors = append(tagErrors, "Fields with name and fields with index are mixed")
 	}
 	if tagErrors != nil {
 		return nil, errors.New(strings.Join(tagErrors, "\n"))
 	}
-	if len(converters) != t.NumField() {
+	if len(m.converters) != len(m.fields) {
 		panic("converters size mismatch")
 	}
-	if len(nameMap) != 0 {
+	if useNames {
 		idxMap = nil
 	} else {
 		nameMap = nil
 	}
 	return &structRowDecoder{
 		structType: t,
-		converters: converters,
+		converters: m.converters,
+		fields:     m.fields,
+		fieldNames: m.fieldNames,
+		transforms: m.transforms,
 		names:      nameMap,
 		indice:     idxMap,
+		defaults:   m.defaults,
+		required:   m.required,
+		groups:     m.groups,
 		opt:        opt,
 	}, nil
 }
@@ -556,54 +1080,217 @@ func newStructDecoder(opt Option, t reflect.Type) (rowDecoder, error) {
 type structRowDecoder struct {
 	structType reflect.Type
 	converters []reflect.Value
+	fields     [][]int
+	fieldNames []string
+	transforms []func(string) string
 	names      map[string]int
 	indice     map[int]int
-	opt        Option
+	defaults   map[int]string
+	required   map[int]bool
+	// absent are the fields with default values whose columns did not appear in the header.
+	absent []int
+	// header is the first line of CSV if fields are mapped by names.
+	header []string
+	groups []*columnGroup
+	opt    Option
 }
 
 func (d *structRowDecoder) consumeHeader(header []string) error {
+	d.header = header
 	indice := make(map[int]int)
 	for i, col := range header {
 		idx, ok := d.names[col]
 		if !ok {
+			d.addGroupColumn(i, col)
 			continue
 		}
 		indice[i] = idx
 		delete(d.names, col)
 	}
 	d.indice = indice
-	if len(d.names) != 0 {
-		var unused []string
-		for n := range d.names {
-			unused = append(unused, n)
+	var unused []string
+	for n, j := range d.names {
+		if _, ok := d.defaults[j]; ok {
+			d.absent = append(d.absent, j)
+			continue
 		}
+		unused = append(unused, n)
+	}
+	if len(unused) != 0 {
 		return fmt.Errorf("%s did not appear in the first line", strings.Join(unused, ", "))
 	}
 	d.names = nil
 	return nil
 }
 
+// addGroupColumn adds a column which is not mapped to a field by name to the groups
+// whose patterns match the column name. If no pattern matches, the column is added to the groups for the rest.
+func (d *structRowDecoder) addGroupColumn(i int, col string) {
+	for _, g := range d.groups {
+		if g.match != nil && g.match(col) {
+			g.addColumn(i, col)
+			return
+		}
+	}
+	for _, g := range d.groups {
+		if g.rest {
+			g.addColumn(i, col)
+		}
+	}
+}
+
+// decodeGroup decodes the columns mapped to the group g and stores them to the field of g.
+func (d *structRowDecoder) decodeGroup(g *columnGroup, row []string, out reflect.Value) error {
+	columns, keys := g.columns, g.keys
+	if !g.needHeader() {
+		to := g.to
+		if to < 0 {
+			to = len(row) - 1
+		} else if to >= len(row) {
+			if d.opt.FieldsPerRecord >= 0 {
+				return fmt.Errorf("Accessed index %d though the size of the row is %d", to, len(row))
+			}
+			to = len(row) - 1
+		}
+		columns = nil
+		for i := g.from; i <= to; i++ {
+			columns = append(columns, i)
+		}
+	}
+	f := fieldByIndex(out.Elem(), d.fields[g.field])
+	var v reflect.Value
+	if f.Kind() == reflect.Map {
+		v = reflect.MakeMapWithSize(f.Type(), len(columns))
+	} else {
+		v = reflect.MakeSlice(f.Type(), 0, len(columns))
+	}
+	for k, i := range columns {
+		if i >= len(row) {
+			if d.opt.FieldsPerRecord < 0 {
+				continue
+			}
+			return fmt.Errorf("Accessed index %d though the size of the row is %d", i, len(row))
+		}
+		e, err := callConverter(d.converters[g.field], d.cell(row, i, g.field))
+		if err != nil {
+			if ve, ok := err.(*ValidationError); ok {
+				ve.Column = d.columnName(i)
+			}
+			return err
+		}
+		if f.Kind() == reflect.Map {
+			v.SetMapIndex(reflect.ValueOf(keys[k]).Convert(f.Type().Key()), e)
+		} else {
+			v = reflect.Append(v, e)
+		}
+	}
+	f.Set(v)
+	return nil
+}
+
+// callConverter converts s with the converter conv.
+func callConverter(conv reflect.Value, s string) (reflect.Value, error) {
+	rets := conv.Call([]reflect.Value{reflect.ValueOf(s)})
+	if len(rets) != 2 {
+		panic("converter must return two values.")
+	}
+	if !rets[1].IsNil() {
+		return reflect.Value{}, rets[1].Interface().(error)
+	}
+	return rets[0], nil
+}
+
 func (d *structRowDecoder) decode(row []string, out reflect.Value) error {
 	// TODO: Reset with zero first.
 	for i, j := range d.indice {
 		if i >= len(row) {
 			if d.opt.FieldsPerRecord < 0 {
+				if err := d.decodeMissing(i, j, out); err != nil {
+					return err
+				}
 				continue
 			}
 			return fmt.Errorf("Accessed index %d though the size of the row is %d", i, len(row))
 		}
-		rets := d.converters[j].Call([]reflect.Value{reflect.ValueOf(row[i])})
+		rets := d.converters[j].Call([]reflect.Value{reflect.ValueOf(d.cell(row, i, j))})
 		if len(rets) != 2 {
 			panic("converter must return two values.")
 		}
 		if !rets[1].IsNil() {
-			return rets[1].Interface().(error)
+			err := rets[1].Interface().(error)
+			if ve, ok := err.(*ValidationError); ok {
+				ve.Column = d.columnName(i)
+			}
+			return err
+		}
+		fieldByIndex(out.Elem(), d.fields[j]).Set(rets[0])
+	}
+	for _, j := range d.absent {
+		if err := d.decodeMissing(-1, j, out); err != nil {
+			return err
+		}
+	}
+	for _, g := range d.groups {
+		if err := d.decodeGroup(g, row, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnName returns the name of the i-th column in the header or i in decimal if the header is not used.
+func (d *structRowDecoder) columnName(i int) string {
+	if d.header != nil && i < len(d.header) {
+		return d.header[i]
+	}
+	return strconv.Itoa(i)
+}
+
+// cell returns the i-th value in row transformed for the j-th field with Option.Transform and the text transformer.
+func (d *structRowDecoder) cell(row []string, i, j int) string {
+	s := row[i]
+	if d.opt.Transform != nil {
+		s = d.opt.Transform(d.columnName(i), s)
+	}
+	if t := d.transforms[j]; t != nil {
+		s = t(s)
+	}
+	return s
+}
+
+// decodeMissing stores the default value of the j-th field to out if the field has default tag
+// when the i-th column is missing in the row. i is negative if the column is absent in the header.
+// decodeMissing reports an error if the field is required and has no default value.
+func (d *structRowDecoder) decodeMissing(i, j int, out reflect.Value) error {
+	def, ok := d.defaults[j]
+	if !ok {
+		if d.required[j] {
+			return &ValidationError{Field: d.fieldNames[j], Column: d.columnName(i), Message: "must not be empty"}
 		}
-		out.Elem().Field(j).Set(rets[0])
+		return nil
+	}
+	v, err := callConverter(d.converters[j], def)
+	if err != nil {
+		return err
 	}
+	fieldByIndex(out.Elem(), d.fields[j]).Set(v)
 	return nil
 }
 
+// fieldByIndex is like reflect.Value.FieldByIndex, but it allocates nil pointers to embedded structs.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
 func (d *structRowDecoder) needHeader() bool {
 	return d.names != nil
 }
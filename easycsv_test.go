@@ -536,3 +536,419 @@ func TestLineNumber(t *testing.T) {
 	noDiff(t, "ints", ints, wantInt)
 	noDiff(t, "lineno", lineno, wantLineno)
 }
+
+type AuditColumns struct {
+	CreatedBy string `name:"created_by"`
+	Revision  int    `name:"revision"`
+}
+
+type AuditIndex struct {
+	CreatedBy string `index:"2"`
+}
+
+type auditIndex struct {
+	UpdatedBy string `index:"3"`
+}
+
+func TestReadEmbeddedStruct(t *testing.T) {
+	f := bytes.NewReader([]byte("name,created_by,revision\nAlice,admin,1\nBob,root,2"))
+	r := NewReader(f)
+	type entry struct {
+		Name string `name:"name"`
+		AuditColumns
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	want := []entry{
+		{Name: "Alice", AuditColumns: AuditColumns{CreatedBy: "admin", Revision: 1}},
+		{Name: "Bob", AuditColumns: AuditColumns{CreatedBy: "root", Revision: 2}},
+	}
+	noDiff(t, "got", got, want)
+}
+
+func TestReadEmbeddedStructPointer(t *testing.T) {
+	f := bytes.NewReader([]byte("Alice,10,admin,root"))
+	r := NewReader(f)
+	var e struct {
+		*AuditIndex
+		auditIndex
+		Name  string `index:"0"`
+		Inner struct {
+			Age int `index:"1"`
+		} `inline:"true"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Name", e.Name, "Alice")
+	noDiff(t, "Age", e.Inner.Age, 10)
+	if e.AuditIndex == nil {
+		t.Fatal("AuditIndex is not allocated")
+	}
+	noDiff(t, "CreatedBy", e.CreatedBy, "admin")
+	noDiff(t, "UpdatedBy", e.UpdatedBy, "root")
+}
+
+func TestNewDecoder_EmbeddedStructConflict(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		Revision string `name:"revision"`
+		AuditColumns
+	}{}))
+	if err == nil || err.Error() != "Fields Revision and AuditColumns.Revision have the same name \"revision\"" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestNewDecoder_UnexportedEmbeddedStructPointer(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		Name string `index:"0"`
+		*auditIndex
+	}{}))
+	if err == nil || err.Error() != "The struct passed to Loop must not have unexported fields: auditIndex" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type address struct {
+	City string `name:"city"`
+	Zip  string `name:"zip"`
+}
+
+func TestReadNestedStructWithPrefix(t *testing.T) {
+	f := bytes.NewReader([]byte("id,billing_city,billing_zip,ship_city,ship_zip,note.city,note.zip\n" +
+		"1,Tokyo,100,Osaka,530,Kyoto,600"))
+	r := NewReader(f)
+	var e struct {
+		ID       int      `name:"id"`
+		Billing  address  `prefix:"billing_"`
+		Shipping *address `prefix:"ship_"`
+		Note     address  `name:"note"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "ID", e.ID, 1)
+	noDiff(t, "Billing", e.Billing, address{City: "Tokyo", Zip: "100"})
+	noDiff(t, "Shipping", e.Shipping, &address{City: "Osaka", Zip: "530"})
+	noDiff(t, "Note", e.Note, address{City: "Kyoto", Zip: "600"})
+}
+
+func TestReadNestedStructMissingColumn(t *testing.T) {
+	f := bytes.NewReader([]byte("ship_city\nOsaka"))
+	r := NewReader(f)
+	var e struct {
+		Shipping address `prefix:"ship_"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "ship_zip did not appear in the first line" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type node struct {
+	V    int   `name:"v"`
+	Next *node `name:"next"`
+}
+
+func TestNewDecoder_RecursiveStruct(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(node{}))
+	if err == nil || err.Error() != "Field Next has a recursive struct type *easycsv.node" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err = newDecoder(Option{}, reflect.TypeOf(struct {
+		Head node `prefix:"head_"`
+	}{}))
+	if err == nil || err.Error() != "Field Head.Next has a recursive struct type *easycsv.node" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadSliceFieldWithSep(t *testing.T) {
+	f := bytes.NewReader([]byte("a;b;c,1|2|3,ff|10\n,4,"))
+	r := NewReader(f)
+	type entry struct {
+		Tags []string `index:"0" sep:";"`
+		Ints []int    `index:"1" sep:"|"`
+		Hex  []int    `index:"2" sep:"|" enc:"hex"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	want := []entry{
+		{Tags: []string{"a", "b", "c"}, Ints: []int{1, 2, 3}, Hex: []int{255, 16}},
+		{Ints: []int{4}},
+	}
+	noDiff(t, "got", got, want)
+}
+
+func TestNewDecoder_SliceFieldErrors(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		Ints  []int      `index:"0"`
+		Int   int        `index:"1" sep:";"`
+		Chans []chan int `index:"2" sep:";"`
+	}{}))
+	want := []string{
+		"Unexpected field type for Ints: []int",
+		"sep is specified to field Int, but the type is int",
+		"Unexpected field type for Chans: chan int",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadColumnRange(t *testing.T) {
+	f := bytes.NewReader([]byte("Alice,1,2,3,x,y\nBob,4,5,6,z"))
+	r := NewReader(f, Option{FieldsPerRecord: -1})
+	type entry struct {
+		Name   string   `index:"0"`
+		Scores []int    `index:"1-3"`
+		Tail   []string `index:"4..."`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	want := []entry{
+		{Name: "Alice", Scores: []int{1, 2, 3}, Tail: []string{"x", "y"}},
+		{Name: "Bob", Scores: []int{4, 5, 6}, Tail: []string{"z"}},
+	}
+	noDiff(t, "got", got, want)
+}
+
+func TestReadColumnRangeOutOfRange(t *testing.T) {
+	f := bytes.NewReader([]byte("1,2"))
+	r := NewReader(f)
+	var e struct {
+		Ints []int `index:"0-2"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "Accessed index 2 though the size of the row is 2" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadColumnPatternAndRest(t *testing.T) {
+	f := bytes.NewReader([]byte("name,score_1,note,score_2,q1,q2\nAlice,10,hello,20,1.5,2.5"))
+	r := NewReader(f)
+	var e struct {
+		Name     string             `name:"name"`
+		Scores   []int              `match:"score_*"`
+		Quarters map[string]float64 `match:"/^q[0-9]$/"`
+		Rest     map[string]string  `rest:"true"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Name", e.Name, "Alice")
+	noDiff(t, "Scores", e.Scores, []int{10, 20})
+	noDiff(t, "Quarters", e.Quarters, map[string]float64{"q1": 1.5, "q2": 2.5})
+	noDiff(t, "Rest", e.Rest, map[string]string{"note": "hello"})
+}
+
+func TestNewDecoder_ColumnGroupErrors(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		Range  int            `index:"1-3"`
+		Rest   []string       `rest:"true"`
+		Map    map[int]string `match:"a_*"`
+		Invert []int          `index:"3-1"`
+		Glob   []int          `match:"[a"`
+		Both   []int          `name:"b" match:"b_*"`
+		Sep    []int          `index:"4..." sep:";"`
+		Def    map[string]int `match:"c_*" default:"0"`
+	}{}))
+	want := []string{
+		"The type of field Range must be a slice or a map with string keys to map multiple columns, but int",
+		"The type of field Rest must be a slice or a map with string keys to map multiple columns, but []string",
+		"The type of field Map must be a slice or a map with string keys to map multiple columns, but map[int]string",
+		"Failed to parse index of field Invert: \"3-1\"",
+		"Failed to parse match of field Glob: syntax error in pattern",
+		"Please specify only one of match, name and index to the struct field: Both",
+		"sep tag is not supported for field Sep to which multiple columns are mapped",
+		"default tag is not supported for field Def to which multiple columns are mapped",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type latLng struct {
+	Lat, Lng float64
+}
+
+type size struct {
+	Width  int `name:"w"`
+	Height int `name:"h"`
+}
+
+func TestReadCompoundStructField(t *testing.T) {
+	f := bytes.NewReader([]byte("name,pos,size,tags\ntower,35.6|139.7,w=10;H=20,a:b\nhome,,h=5,"))
+	r := NewReader(f)
+	type entry struct {
+		Name string  `name:"name"`
+		Pos  *latLng `name:"pos" sep:"|"`
+		Size size    `name:"size" sep:";" kv:"="`
+		Tags struct {
+			Values []string `sep:":"`
+		} `name:"tags" sep:","`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	noDiff(t, "Pos", []*latLng{got[0].Pos, got[1].Pos}, []*latLng{{Lat: 35.6, Lng: 139.7}, nil})
+	noDiff(t, "Size", []size{got[0].Size, got[1].Size}, []size{{Width: 10, Height: 20}, {Height: 5}})
+	noDiff(t, "Tags", got[0].Tags.Values, []string{"a", "b"})
+}
+
+func TestReadCompoundStructFieldErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		suberr string
+	}{
+		{input: "1,2,3", suberr: "\"1,2,3\" has 3 components, but easycsv.latLng has 2 fields"},
+		{input: "1,x", suberr: "parsing \"x\""},
+	}
+	for _, test := range tests {
+		r := NewReader(bytes.NewBufferString(test.input), Option{Comma: '\t'})
+		var e struct {
+			Pos latLng `index:"0" sep:","`
+		}
+		if r.Read(&e) {
+			t.Errorf("Read returned true unexpectedly for %q", test.input)
+		}
+		if err := r.Done(); err == nil || !strings.Contains(err.Error(), test.suberr) {
+			t.Errorf("%v does not contain %q", err, test.suberr)
+		}
+	}
+	r := NewReader(bytes.NewBufferString("w=1;d=2"))
+	var e struct {
+		Size size `index:"0" sep:";" kv:"="`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "Unknown key \"d\" in \"w=1;d=2\" for easycsv.size" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadDefaultValues(t *testing.T) {
+	f := bytes.NewReader([]byte("name,amount\nAlice,10\nBob,\nCarol"))
+	r := NewReader(f, Option{FieldsPerRecord: -1})
+	type entry struct {
+		Name     string `name:"name"`
+		Amount   int    `name:"amount" default:"1"`
+		Currency string `name:"currency" default:"USD"`
+		Rate     *int   `name:"rate" default:"100"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	rate := 100
+	want := []entry{
+		{Name: "Alice", Amount: 10, Currency: "USD", Rate: &rate},
+		{Name: "Bob", Amount: 1, Currency: "USD", Rate: &rate},
+		{Name: "Carol", Amount: 1, Currency: "USD", Rate: &rate},
+	}
+	noDiff(t, "got", got, want)
+}
+
+func TestReadDefaultValuesWithIndex(t *testing.T) {
+	f := bytes.NewReader([]byte("a,NA\nb"))
+	r := NewReader(f, Option{FieldsPerRecord: -1, NullValues: []string{"NA"}})
+	type entry struct {
+		Name  string  `index:"0"`
+		Score float64 `index:"1" default:"0.5"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	noDiff(t, "got", got, []entry{{Name: "a", Score: 0.5}, {Name: "b", Score: 0.5}})
+}
+
+func TestNewDecoder_InvalidDefaultValue(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		Count int `index:"0" default:"many"`
+	}{}))
+	if err == nil || !strings.HasPrefix(err.Error(), "Failed to decode the default value of field Count: ") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadNameWithPatternCharacters(t *testing.T) {
+	f := bytes.NewReader([]byte("qty?,price[usd],a*\n3,1.5,x"))
+	r := NewReader(f)
+	var e struct {
+		Qty   int     `name:"qty?"`
+		Price float64 `name:"price[usd]"`
+		Star  string  `name:"a*"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Qty", e.Qty, 3)
+	noDiff(t, "Price", e.Price, 1.5)
+	noDiff(t, "Star", e.Star, "x")
+}
+
+type packedPoint struct {
+	X, Y int
+	z    int
+}
+
+func TestReadCompoundStructFieldWithUnexportedFields(t *testing.T) {
+	f := bytes.NewReader([]byte("p\n1:2"))
+	r := NewReader(f)
+	var e struct {
+		P packedPoint `name:"p" sep:":"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "X", e.P.X, 1)
+	noDiff(t, "Y", e.P.Y, 2)
+
+	r = NewReader(bytes.NewReader([]byte("1:2")))
+	var i struct {
+		P packedPoint `index:"0" sep:":"`
+	}
+	if !r.Read(&i) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "X", i.P.X, 1)
+	noDiff(t, "Y", i.P.Y, 2)
+}
+
+func TestNewDecoder_IndexOverlaps(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		A []int `index:"0-2"`
+		B int   `index:"1"`
+		C []int `index:"2..."`
+		D []int `index:"5-6"`
+	}{}))
+	want := []string{
+		"Fields A and B have the same index 1",
+		"The index ranges of fields A and C overlap",
+		"The index ranges of fields C and D overlap",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err = newDecoder(Option{AutoIndex: true}, reflect.TypeOf(struct {
+		A []int `index:"1..."`
+		B int
+	}{}))
+	if err == nil || err.Error() != "Fields A and B have the same index 2" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
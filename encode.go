@@ -1,24 +1,474 @@
 package easycsv
 
 import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 )
 
-var predefinedDecoders = map[string]func(t reflect.Type) interface{}{
-	"hex": func(t reflect.Type) interface{} {
+// Unmarshaler is the interface implemented by types that can decode a value in CSV into themselves.
+// Fields whose pointer types implement Unmarshaler or encoding.TextUnmarshaler are decoded
+// with UnmarshalCSV or UnmarshalText unless custom decoders are specified in Option.
+type Unmarshaler interface {
+	UnmarshalCSV(string) error
+}
+
+var predefinedDecoders = map[string]func(opt Option, t reflect.Type) interface{}{
+	"hex": func(opt Option, t reflect.Type) interface{} {
 		return createIntConverter(t, 16)
 	},
-	"oct": func(t reflect.Type) interface{} {
+	"oct": func(opt Option, t reflect.Type) interface{} {
 		return createIntConverter(t, 8)
 	},
-	"deci": func(t reflect.Type) interface{} {
-		return createIntConverter(t, 10)
+	"deci": func(opt Option, t reflect.Type) interface{} {
+		return normalizeNumberConverter(opt, createIntConverter(t, 10))
+	},
+	"raw": func(opt Option, t reflect.Type) interface{} {
+		return createBytesConverter(t, decodeRawBytes)
+	},
+	"base64": func(opt Option, t reflect.Type) interface{} {
+		return createBytesConverter(t, base64.StdEncoding.DecodeString)
+	},
+	"base64url": func(opt Option, t reflect.Type) interface{} {
+		return createBytesConverter(t, base64.URLEncoding.DecodeString)
+	},
+	"hexbytes": func(opt Option, t reflect.Type) interface{} {
+		return createBytesConverter(t, decodeHexBytes)
+	},
+	"json": func(opt Option, t reflect.Type) interface{} {
+		return createJSONConverter(t)
+	},
+	"percent": func(opt Option, t reflect.Type) interface{} {
+		return createFloatConverter(t, parsePercent)
+	},
+	"currency": func(opt Option, t reflect.Type) interface{} {
+		return createFloatConverter(t, func(s string) (float64, error) {
+			return parseCurrency(opt, s)
+		})
+	},
+	"bytesize": func(opt Option, t reflect.Type) interface{} {
+		return createInt64Converter(t, parseByteSize)
+	},
+	"duration": func(opt Option, t reflect.Type) interface{} {
+		if t != durationType {
+			return nil
+		}
+		return time.ParseDuration
+	},
+	"seconds": func(opt Option, t reflect.Type) interface{} {
+		return createDurationConverter(t, time.Second)
+	},
+	"millis": func(opt Option, t reflect.Type) interface{} {
+		return createDurationConverter(t, time.Millisecond)
+	},
+	"unix": func(opt Option, t reflect.Type) interface{} {
+		return createUnixTimeConverter(opt, t, time.Second)
+	},
+	"unixms": func(opt Option, t reflect.Type) interface{} {
+		return createUnixTimeConverter(opt, t, time.Millisecond)
 	},
+	"unixns": func(opt Option, t reflect.Type) interface{} {
+		return createUnixTimeConverter(opt, t, time.Nanosecond)
+	},
+}
+
+var stringType = reflect.TypeOf("")
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+var bigIntType = reflect.TypeOf(big.Int{})
+var bigFloatType = reflect.TypeOf(big.Float{})
+var bigRatType = reflect.TypeOf(big.Rat{})
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// standardTypeDecoders are the default decoders for types in the standard library that do not implement
+// encoding.TextUnmarshaler. Other types like net.IP, netip.Addr and netip.Prefix are decoded with UnmarshalText.
+var standardTypeDecoders = map[reflect.Type]interface{}{
+	reflect.TypeOf(net.HardwareAddr(nil)): net.ParseMAC,
+	reflect.TypeOf(url.URL{}): func(s string) (url.URL, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return *u, nil
+	},
+	reflect.TypeOf(mail.Address{}): func(s string) (mail.Address, error) {
+		a, err := mail.ParseAddress(s)
+		if err != nil {
+			return mail.Address{}, err
+		}
+		return *a, nil
+	},
+}
+
+// defaultInferTypes are the types tried to decode values into interface{} if Option.InferTypes is nil.
+var defaultInferTypes = []reflect.Type{
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(float64(0)),
+	reflect.TypeOf(false),
+	timeType,
+}
+
+// errorValue returns err as a reflect.Value of error type.
+func errorValue(err error) reflect.Value {
+	if err == nil {
+		return reflect.Zero(errorType)
+	}
+	return reflect.ValueOf(&err).Elem()
+}
+
+// timeLayouts maps the names of layouts defined in the time package to the layouts.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+func createTimeConverter(layout string, loc *time.Location) interface{} {
+	if layout == "" {
+		layout = time.RFC3339
+	} else if l, ok := timeLayouts[layout]; ok {
+		layout = l
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(s string) (time.Time, error) {
+		return time.ParseInLocation(layout, s, loc)
+	}
+}
+
+func decodeRawBytes(s string) ([]byte, error) {
+	return []byte(s), nil
+}
+
+// decodeHexBytes decodes hex strings. Dashes in inputs (e.g. UUIDs) are ignored.
+func decodeHexBytes(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Replace(s, "-", "", -1))
+}
+
+// createBytesConverter creates a converter to a byte slice or a byte array type t with decode.
+// It returns nil for other types. For byte arrays, the length of decoded bytes must be the length of the arrays.
+func createBytesConverter(t reflect.Type, decode func(string) ([]byte, error)) interface{} {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array || t.Elem().Kind() != reflect.Uint8 {
+		return nil
+	}
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		b, err := decode(args[0].String())
+		if err != nil {
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		if t.Kind() == reflect.Slice {
+			return []reflect.Value{reflect.ValueOf(b).Convert(t), reflect.Zero(errorType)}
+		}
+		if len(b) != t.Len() {
+			err := fmt.Errorf("%v requires %d bytes, but got %d bytes", t, t.Len(), len(b))
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		v := reflect.New(t).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// createJSONConverter creates a converter to t which parses inputs as JSON with encoding/json.
+// An empty input is converted to the zero value.
+func createJSONConverter(t reflect.Type) interface{} {
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		p := reflect.New(t)
+		if s := args[0].String(); s != "" {
+			if err := json.Unmarshal([]byte(s), p.Interface()); err != nil {
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+		}
+		return []reflect.Value{p.Elem(), reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// createBoolConverter creates a converter to bool which accepts trueValues and falseValues case-insensitively.
+// Other inputs are parsed with strconv.ParseBool.
+func createBoolConverter(trueValues, falseValues []string) interface{} {
+	return func(s string) (bool, error) {
+		for _, v := range trueValues {
+			if strings.EqualFold(s, v) {
+				return true, nil
+			}
+		}
+		for _, v := range falseValues {
+			if strings.EqualFold(s, v) {
+				return false, nil
+			}
+		}
+		return strconv.ParseBool(s)
+	}
+}
+
+// createFloatConverter creates a converter to a float type t with parse. It returns nil for other types.
+func createFloatConverter(t reflect.Type, parse func(string) (float64, error)) interface{} {
+	switch t.Kind() {
+	case reflect.Float32:
+		return convertConverter(func(s string) (float32, error) {
+			f, err := parse(s)
+			return float32(f), err
+		}, t)
+	case reflect.Float64:
+		return convertConverter(parse, t)
+	default:
+		return nil
+	}
+}
+
+// createInt64Converter creates a converter to an integer type t with parse.
+// The converter reports an error if the parsed value overflows t. It returns nil for non-integer types.
+func createInt64Converter(t reflect.Type, parse func(string) (int64, error)) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil
+	}
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		i, err := parse(s)
+		if err != nil {
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		v := reflect.New(t).Elem()
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if i < 0 || v.OverflowUint(uint64(i)) {
+				return []reflect.Value{reflect.Zero(t), errorValue(fmt.Errorf("%q overflows %v", s, t))}
+			}
+			v.SetUint(uint64(i))
+		default:
+			if v.OverflowInt(i) {
+				return []reflect.Value{reflect.Zero(t), errorValue(fmt.Errorf("%q overflows %v", s, t))}
+			}
+			v.SetInt(i)
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// parsePercent parses a percentage like "12.5%" into a ratio (0.125). The percent sign is optional.
+func parsePercent(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%")), 64)
+	return f / 100, err
 }
 
+// parseCurrency parses an amount of money like "$1,200.00", "USD 1,200" or "(300)".
+// Currency symbols and codes before or after amounts are ignored and amounts in parentheses are negative.
+// Digit groups are separated by "," unless ThousandsSeparator and DecimalSeparator are specified in opt.
+func parseCurrency(opt Option, s string) (float64, error) {
+	v := strings.TrimSpace(s)
+	negative := strings.HasPrefix(v, "(") && strings.HasSuffix(v, ")")
+	if negative {
+		v = v[1 : len(v)-1]
+	}
+	sign := ""
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		sign, v = v[:1], v[1:]
+	}
+	isSymbol := func(r rune) bool {
+		return unicode.Is(unicode.Sc, r) || unicode.IsLetter(r)
+	}
+	v = strings.TrimFunc(v, func(r rune) bool {
+		return isSymbol(r) || unicode.IsSpace(r)
+	})
+	if strings.IndexFunc(v, isSymbol) >= 0 {
+		return 0, fmt.Errorf("Failed to parse %q as currency", s)
+	}
+	v = sign + v
+	if normalize := opt.numberNormalizer(); normalize != nil {
+		var err error
+		if v, err = normalize(v); err != nil {
+			return 0, fmt.Errorf("Failed to parse %q as currency", s)
+		}
+	} else {
+		v = strings.Replace(v, ",", "", -1)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse %q as currency", s)
+	}
+	if negative {
+		f = -f
+	}
+	return f, nil
+}
+
+// byteSizeUnits maps the units of byte sizes in lower case to the numbers of bytes.
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// parseByteSize parses a human-readable byte size like "10MB" or "1.5GiB" into the number of bytes.
+// Units are case-insensitive. KB, MB, ... are powers of 1000 and KiB, MiB, ... are powers of 1024.
+func parseByteSize(s string) (int64, error) {
+	v := strings.TrimSpace(s)
+	i := strings.IndexFunc(v, unicode.IsLetter)
+	if i < 0 {
+		i = len(v)
+	}
+	num, unit := strings.TrimSpace(v[:i]), strings.ToLower(v[i:])
+	mul, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("Unknown unit of byte size in %q", s)
+	}
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n > math.MaxInt64/mul || n < math.MinInt64/mul {
+			return 0, fmt.Errorf("%q overflows int64", s)
+		}
+		return n * mul, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse %q as byte size", s)
+	}
+	f *= float64(mul)
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("%q overflows int64", s)
+	}
+	return int64(math.Round(f)), nil
+}
+
+// createDurationConverter returns a converter that parses a number (e.g. "1.5") in unit into time.Duration.
+func createDurationConverter(t reflect.Type, unit time.Duration) interface{} {
+	if t != durationType {
+		return nil
+	}
+	return func(s string) (time.Duration, error) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Duration(i) * unit, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return time.Duration(f * float64(unit)), err
+	}
+}
+
+// createUnixTimeConverter returns a converter that parses the elapsed time since
+// the Unix epoch in unit into time.Time or int64.
+func createUnixTimeConverter(opt Option, t reflect.Type, unit time.Duration) interface{} {
+	switch t {
+	case timeType:
+		loc := opt.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		return func(s string) (time.Time, error) {
+			i, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			ns := int64(unit)
+			return time.Unix(i/(int64(time.Second)/ns), i%(int64(time.Second)/ns)*ns).In(loc), nil
+		}
+	case reflect.TypeOf(int64(0)):
+		return func(s string) (int64, error) {
+			return strconv.ParseInt(s, 10, 64)
+		}
+	default:
+		return nil
+	}
+}
+
+// createIntConverter creates a converter to an integer type t which parses inputs in base.
 func createIntConverter(t reflect.Type, base int) interface{} {
+	if t == bigIntType {
+		return createBigIntConverter(base)
+	}
+	return convertConverter(createBuiltinIntConverter(t, base), t)
+}
+
+// createBigIntConverter creates a converter to big.Int which parses inputs in base.
+func createBigIntConverter(base int) interface{} {
+	return func(s string) (big.Int, error) {
+		var i big.Int
+		if _, ok := i.SetString(s, base); !ok {
+			return i, fmt.Errorf("Failed to parse %q as big.Int", s)
+		}
+		return i, nil
+	}
+}
+
+// createBigConverter creates a converter to big.Int, big.Float or big.Rat.
+// big.Float is parsed with the precision large enough to hold all digits in inputs.
+// It returns nil for other types.
+func createBigConverter(t reflect.Type) interface{} {
+	switch t {
+	case bigIntType:
+		return createBigIntConverter(0)
+	case bigFloatType:
+		return func(s string) (big.Float, error) {
+			prec := uint(len(s))*4 + 64
+			f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+			if err != nil {
+				return big.Float{}, err
+			}
+			return *f, nil
+		}
+	case bigRatType:
+		return func(s string) (big.Rat, error) {
+			var r big.Rat
+			if _, ok := r.SetString(s); !ok {
+				return r, fmt.Errorf("Failed to parse %q as big.Rat", s)
+			}
+			return r, nil
+		}
+	default:
+		return nil
+	}
+}
+
+// createBuiltinIntConverter creates a converter to the builtin integer type whose kind is the same as t.
+func createBuiltinIntConverter(t reflect.Type, base int) interface{} {
 	switch t.Kind() {
 	case reflect.Int:
 		return func(s string) (int, error) {
@@ -67,7 +517,7 @@ func createIntConverter(t reflect.Type, base int) interface{} {
 		}
 	case reflect.Uint64:
 		return func(s string) (uint64, error) {
-			i, err := strconv.ParseUint(s, base, 32)
+			i, err := strconv.ParseUint(s, base, 64)
 			return uint64(i), err
 		}
 	default:
@@ -75,6 +525,53 @@ func createIntConverter(t reflect.Type, base int) interface{} {
 	}
 }
 
+// convertConverter adapts conv, a converter to a builtin type, to a converter to t whose underlying type
+// is the builtin type (e.g. `type UserID int64`). conv is returned as it is if conv returns t or conv is nil.
+func convertConverter(conv interface{}, t reflect.Type) interface{} {
+	if conv == nil {
+		return nil
+	}
+	c := reflect.ValueOf(conv)
+	if c.Type().Out(0) == t {
+		return conv
+	}
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		rets := c.Call(args)
+		return []reflect.Value{rets[0].Convert(t), rets[1]}
+	}).Interface()
+}
+
+// preprocessConverter creates a converter which applies f to inputs before converting them with conv.
+// conv is returned as it is if conv or f is nil.
+func preprocessConverter(conv interface{}, f func(string) string) interface{} {
+	if conv == nil || f == nil {
+		return conv
+	}
+	c := reflect.ValueOf(conv)
+	in := c.Type().In(0)
+	return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+		return c.Call([]reflect.Value{reflect.ValueOf(f(args[0].String())).Convert(in)})
+	}).Interface()
+}
+
+// normalizeNumberConverter creates a converter which normalizes numbers with Option.numberNormalizer before conv.
+func normalizeNumberConverter(opt Option, conv interface{}) interface{} {
+	normalize := opt.numberNormalizer()
+	if conv == nil || normalize == nil {
+		return conv
+	}
+	c := reflect.ValueOf(conv)
+	in := c.Type().In(0)
+	return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+		s, err := normalize(args[0].String())
+		if err != nil {
+			return []reflect.Value{reflect.Zero(c.Type().Out(0)), errorValue(err)}
+		}
+		return c.Call([]reflect.Value{reflect.ValueOf(s).Convert(in)})
+	}).Interface()
+}
+
 func validateTypeDecoder(t reflect.Type, conv interface{}) error {
 	convT := reflect.TypeOf(conv)
 	if convT.Kind() != reflect.Func {
@@ -93,16 +590,452 @@ func validateTypeDecoder(t reflect.Type, conv interface{}) error {
 	return nil
 }
 
-func createConverterFromType(opt Option, t reflect.Type) (interface{}, error) {
-	if opt.TypeDecoders != nil {
-		if conv, ok := opt.TypeDecoders[t]; ok {
-			if err := validateTypeDecoder(t, conv); err != nil {
-				return nil, err
+// createPtrConverter creates a converter to a pointer type t from elemConv, which is a converter to t.Elem().
+// The converter returns nil if the input is null.
+func createPtrConverter(opt Option, t reflect.Type, elemConv interface{}) interface{} {
+	conv := reflect.ValueOf(elemConv)
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		if opt.isNull(args[0].String()) {
+			return []reflect.Value{reflect.Zero(t), reflect.Zero(errorType)}
+		}
+		rets := conv.Call(args)
+		if !rets[1].IsNil() {
+			return []reflect.Value{reflect.Zero(t), rets[1]}
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(rets[0])
+		return []reflect.Value{p, rets[1]}
+	}).Interface()
+}
+
+// createNullConverter creates a converter which handles null inputs with NullPolicy in opt
+// and converts other inputs with conv, a converter to t. name is the name of the field used in errors.
+// conv is returned as it is if t is a pointer type or a null type in database/sql, which handle null inputs by themselves.
+func createNullConverter(opt Option, t reflect.Type, conv interface{}, name string) interface{} {
+	if opt.NullPolicy == NullParse || t.Kind() == reflect.Ptr || isSQLNullType(t) {
+		return conv
+	}
+	c := reflect.ValueOf(conv)
+	return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		if !opt.isNull(s) {
+			return c.Call(args)
+		}
+		if opt.NullPolicy == NullError {
+			return []reflect.Value{reflect.Zero(t), errorValue(fmt.Errorf("%s must not be null, but got %q", name, s))}
+		}
+		return []reflect.Value{reflect.Zero(t), reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// createDefaultValueConverter creates a converter which converts def instead of null inputs with conv.
+func createDefaultValueConverter(opt Option, conv interface{}, def string) interface{} {
+	c := reflect.ValueOf(conv)
+	return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+		if opt.isNull(args[0].String()) {
+			return c.Call([]reflect.Value{reflect.ValueOf(def).Convert(c.Type().In(0))})
+		}
+		return c.Call(args)
+	}).Interface()
+}
+
+// isSQLNullType returns true if t is a null type in database/sql (e.g. sql.NullString or sql.Null[T]),
+// which is a struct of a value and Valid.
+func isSQLNullType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null") &&
+		t.NumField() == 2 && t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// createSQLNullConverter creates a converter to a null type t in database/sql.
+// Valid is set to false for null inputs. Otherwise, inputs are converted to the value field of t.
+// It returns nil if t is not a null type or the value field can not be converted.
+func createSQLNullConverter(opt Option, t reflect.Type) (interface{}, error) {
+	if !isSQLNullType(t) {
+		return nil, nil
+	}
+	conv, err := createConverterFromType(opt, t.Field(0).Type)
+	if conv == nil || err != nil {
+		return nil, err
+	}
+	c := reflect.ValueOf(conv)
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		v := reflect.New(t).Elem()
+		if opt.isNull(args[0].String()) {
+			return []reflect.Value{v, reflect.Zero(errorType)}
+		}
+		e, err := callConverter(c, args[0].String())
+		if err != nil {
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		v.Field(0).Set(e)
+		v.Field(1).SetBool(true)
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface(), nil
+}
+
+// createUnmarshalerConverter creates a converter to t if the pointer to t implements
+// Unmarshaler or encoding.TextUnmarshaler. It returns nil otherwise.
+func createUnmarshalerConverter(t reflect.Type) interface{} {
+	var unmarshal func(p interface{}, s string) error
+	if pt := reflect.PtrTo(t); pt.Implements(unmarshalerType) {
+		unmarshal = func(p interface{}, s string) error {
+			return p.(Unmarshaler).UnmarshalCSV(s)
+		}
+	} else if pt.Implements(textUnmarshalerType) {
+		unmarshal = func(p interface{}, s string) error {
+			return p.(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+		}
+	} else {
+		return nil
+	}
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		p := reflect.New(t)
+		if err := unmarshal(p.Interface(), args[0].String()); err != nil {
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		return []reflect.Value{p.Elem(), reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// createSplitConverter creates a converter to a slice type t that splits inputs with sep
+// and converts each element with elemConv. An empty input is converted to an empty slice.
+func createSplitConverter(t reflect.Type, sep string, elemConv interface{}) interface{} {
+	conv := reflect.ValueOf(elemConv)
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		if s == "" {
+			return []reflect.Value{reflect.Zero(t), reflect.Zero(errorType)}
+		}
+		elems := strings.Split(s, sep)
+		v := reflect.MakeSlice(t, 0, len(elems))
+		for _, e := range elems {
+			rets := conv.Call([]reflect.Value{reflect.ValueOf(e)})
+			if !rets[1].IsNil() {
+				return []reflect.Value{reflect.Zero(t), rets[1]}
+			}
+			v = reflect.Append(v, rets[0])
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// compoundField is a field of a struct decoded from a single value by createCompoundConverter.
+type compoundField struct {
+	index     int
+	key       string
+	converter reflect.Value
+}
+
+// createCompoundConverter creates a converter to a struct type t that splits inputs with sep and
+// converts the components to fields. If kv is not empty, each component is a pair of a key and a value
+// separated by kv. Otherwise, the components are stored to fields in order.
+// An empty input is converted to the zero value.
+func createCompoundConverter(t reflect.Type, sep, kv string, fields []compoundField) interface{} {
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		v := reflect.New(t).Elem()
+		fail := func(err error) []reflect.Value {
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		if s == "" {
+			return []reflect.Value{v, reflect.Zero(errorType)}
+		}
+		parts := strings.Split(s, sep)
+		if kv == "" && len(parts) != len(fields) {
+			return fail(fmt.Errorf("%q has %d components, but %v has %d fields", s, len(parts), t, len(fields)))
+		}
+		for i, p := range parts {
+			var f *compoundField
+			if kv == "" {
+				f = &fields[i]
+			} else {
+				pair := strings.SplitN(p, kv, 2)
+				if len(pair) != 2 {
+					return fail(fmt.Errorf("%q in %q is not a key-value pair", p, s))
+				}
+				for j := range fields {
+					if strings.EqualFold(fields[j].key, pair[0]) {
+						f = &fields[j]
+						break
+					}
+				}
+				if f == nil {
+					return fail(fmt.Errorf("Unknown key %q in %q for %v", pair[0], s, t))
+				}
+				p = pair[1]
+			}
+			e, err := callConverter(f.converter, p)
+			if err != nil {
+				return fail(err)
+			}
+			v.Field(f.index).Set(e)
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// findInterfaceDecoder returns the interface type implemented by t and the decoder registered
+// for the interface in TypeDecoders. It returns nil if no such decoder is registered.
+func findInterfaceDecoder(opt Option, t reflect.Type) (reflect.Type, interface{}, error) {
+	if t.Kind() == reflect.Interface {
+		return nil, nil, nil
+	}
+	var found []reflect.Type
+	for it := range opt.TypeDecoders {
+		if it.Kind() == reflect.Interface && t.Implements(it) {
+			found = append(found, it)
+		}
+	}
+	if len(found) == 0 {
+		return nil, nil, nil
+	}
+	if len(found) > 1 {
+		var names []string
+		for _, it := range found {
+			names = append(names, it.String())
+		}
+		sort.Strings(names)
+		return nil, nil, fmt.Errorf("%v implements multiple interfaces registered in TypeDecoders: %s", t, strings.Join(names, ", "))
+	}
+	it := found[0]
+	conv := opt.TypeDecoders[it]
+	if err := validateTypeDecoder(it, conv); err != nil {
+		return nil, nil, err
+	}
+	return it, conv, nil
+}
+
+// createInterfaceConverter creates a converter to t from conv, which is a converter to an interface type it.
+// The converter reports an error if conv returns a value whose type is not t.
+func createInterfaceConverter(t, it reflect.Type, conv interface{}) interface{} {
+	c := reflect.ValueOf(conv)
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		rets := c.Call(args)
+		if !rets[1].IsNil() {
+			return []reflect.Value{reflect.Zero(t), rets[1]}
+		}
+		if rets[0].IsNil() {
+			return []reflect.Value{reflect.Zero(t), reflect.Zero(errorType)}
+		}
+		v := rets[0].Elem()
+		if v.Type() != t {
+			err := fmt.Errorf("The decoder for %v returned %v, but %v is expected", it, v.Type(), t)
+			return []reflect.Value{reflect.Zero(t), errorValue(err)}
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface()
+}
+
+// createEnumConverter creates a converter to an integer type t which looks up inputs in enum, a table from names to values.
+// The values must be t or int.
+// The converter reports an error with the list of allowed names if an input is not found in enum.
+// Names are compared case-insensitively if Option.EnumCaseInsensitive is true.
+func createEnumConverter(opt Option, t reflect.Type, enum map[string]interface{}) (interface{}, error) {
+	isUint := false
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		isUint = true
+	default:
+		return nil, fmt.Errorf("The key of Enums must be an integer type, but got %v", t)
+	}
+	values := make(map[string]reflect.Value)
+	var names []string
+	for name, value := range enum {
+		v := reflect.ValueOf(value)
+		if v.IsValid() && v.Type() == reflect.TypeOf(0) {
+			// Untyped constants (e.g. 0) are stored as int.
+			i := v.Int()
+			if isUint && (i < 0 || reflect.Zero(t).OverflowUint(uint64(i))) || !isUint && reflect.Zero(t).OverflowInt(i) {
+				return nil, fmt.Errorf("The value of %q in Enums for %v overflows %v: %d", name, t, t, i)
+			}
+		} else if !v.IsValid() || v.Type() != t {
+			return nil, fmt.Errorf("The value of %q in Enums for %v must be %v, but got %T", name, t, t, value)
+		}
+		key := name
+		if opt.EnumCaseInsensitive {
+			key = strings.ToLower(name)
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("%q in Enums for %v is duplicated", name, t)
+		}
+		values[key] = v.Convert(t)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		key := args[0].String()
+		if opt.EnumCaseInsensitive {
+			key = strings.ToLower(key)
+		}
+		if v, ok := values[key]; ok {
+			return []reflect.Value{v, reflect.Zero(errorType)}
+		}
+		err := fmt.Errorf("%q is not a valid %v: allowed values are %s", args[0].String(), t, strings.Join(names, ", "))
+		return []reflect.Value{reflect.Zero(t), errorValue(err)}
+	}).Interface(), nil
+}
+
+// createInferTypeConverter creates a converter to t used to infer types in createInferConverter.
+// The converter is stricter than the default converter of t unless a decoder for t is registered in TypeDecoders:
+// integers are parsed in base 10, bool accepts only "true", "false", TrueValues and FalseValues case-insensitively
+// and floats reject NaN and infinity so that words like "T" and "NaN" in text columns are kept as strings.
+func createInferTypeConverter(opt Option, t reflect.Type) (interface{}, error) {
+	if _, ok := opt.TypeDecoders[t]; ok {
+		return createConverterFromType(opt, t)
+	}
+	if c := createBuiltinIntConverter(t, 10); c != nil {
+		return normalizeNumberConverter(opt, convertConverter(c, t)), nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		trueValues := append([]string{"true"}, opt.TrueValues...)
+		falseValues := append([]string{"false"}, opt.FalseValues...)
+		return convertConverter(func(s string) (bool, error) {
+			for _, v := range trueValues {
+				if strings.EqualFold(s, v) {
+					return true, nil
+				}
 			}
-			return conv, nil
+			for _, v := range falseValues {
+				if strings.EqualFold(s, v) {
+					return false, nil
+				}
+			}
+			return false, fmt.Errorf("%q is not a bool", s)
+		}, t), nil
+	case reflect.Float32, reflect.Float64:
+		conv, err := createConverterFromType(opt, t)
+		if conv == nil || err != nil {
+			return conv, err
 		}
+		c := reflect.ValueOf(conv)
+		return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+			rets := c.Call(args)
+			if f := rets[0].Float(); rets[1].IsNil() && (math.IsNaN(f) || math.IsInf(f, 0)) {
+				err := fmt.Errorf("%q is not a finite number", args[0].String())
+				return []reflect.Value{reflect.Zero(t), errorValue(err)}
+			}
+			return rets
+		}).Interface(), nil
+	}
+	return createConverterFromType(opt, t)
+}
+
+// createInferConverter creates a converter to an empty interface type t which decodes inputs into
+// the first type in Option.InferTypes that succeeds. Inputs are stored as strings if no type succeeds.
+// Integers are parsed in base 10 so that numbers with leading zeros (e.g. "007") are not parsed as octal.
+func createInferConverter(opt Option, t reflect.Type) (interface{}, error) {
+	types := opt.InferTypes
+	if types == nil {
+		types = defaultInferTypes
+	}
+	var convs []reflect.Value
+	for _, it := range types {
+		if it.Kind() == reflect.Interface {
+			return nil, fmt.Errorf("InferTypes must not contain interface types, but got %v", it)
+		}
+		conv, err := createInferTypeConverter(opt, it)
+		if err != nil {
+			return nil, err
+		}
+		if conv == nil {
+			return nil, fmt.Errorf("Failed to create a converter for %v in InferTypes", it)
+		}
+		convs = append(convs, reflect.ValueOf(conv))
+	}
+	fnType := reflect.FuncOf([]reflect.Type{stringType}, []reflect.Type{t, errorType}, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		v := reflect.New(t).Elem()
+		if opt.isNull(s) {
+			return []reflect.Value{v, reflect.Zero(errorType)}
+		}
+		v.Set(reflect.ValueOf(s))
+		for _, conv := range convs {
+			if e, err := callConverter(conv, s); err == nil {
+				v.Set(e)
+				break
+			}
+		}
+		return []reflect.Value{v, reflect.Zero(errorType)}
+	}).Interface(), nil
+}
+
+// createConverterFromType creates a converter to t. The decoder registered for t in TypeDecoders is used first.
+// Decoders registered for interface types in TypeDecoders are used only if t has no builtin converter.
+func createConverterFromType(opt Option, t reflect.Type) (interface{}, error) {
+	if conv, ok := opt.TypeDecoders[t]; ok {
+		if err := validateTypeDecoder(t, conv); err != nil {
+			return nil, err
+		}
+		return conv, nil
+	}
+	conv, err := createBuiltinConverter(opt, t)
+	if conv != nil || err != nil || opt.TypeDecoders == nil {
+		return conv, err
+	}
+	it, conv, err := findInterfaceDecoder(opt, t)
+	if conv == nil || err != nil {
+		return nil, err
+	}
+	return createInterfaceConverter(t, it, conv), nil
+}
+
+// createBuiltinConverter creates a converter to t without decoders for interface types in TypeDecoders.
+func createBuiltinConverter(opt Option, t reflect.Type) (interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		conv, err := createConverterFromType(opt, t.Elem())
+		if conv == nil || err != nil {
+			return nil, err
+		}
+		return createPtrConverter(opt, t, conv), nil
+	}
+	if enum, ok := opt.Enums[t]; ok {
+		return createEnumConverter(opt, t, enum)
+	}
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		return createInferConverter(opt, t)
+	}
+	if conv, ok := standardTypeDecoders[t]; ok {
+		return conv, nil
+	}
+	if t == timeType {
+		return createTimeConverter(opt.TimeLayout, opt.Location), nil
+	}
+	if t == durationType {
+		return time.ParseDuration, nil
+	}
+	if conv := createBigConverter(t); conv != nil {
+		return conv, nil
+	}
+	if conv, err := createSQLNullConverter(opt, t); conv != nil || err != nil {
+		return conv, err
+	}
+	if conv := createUnmarshalerConverter(t); conv != nil {
+		return conv, nil
+	}
+	if t.Kind() == reflect.Bool && (opt.TrueValues != nil || opt.FalseValues != nil) {
+		return convertConverter(createBoolConverter(opt.TrueValues, opt.FalseValues), t), nil
+	}
+	if conv := createBytesConverter(t, decodeRawBytes); conv != nil {
+		return conv, nil
+	}
+	conv := convertConverter(createDefaultConverter(t), t)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		conv = normalizeNumberConverter(opt, conv)
 	}
-	return createDefaultConverter(t), nil
+	return conv, nil
 }
 
 func createDefaultConverter(t reflect.Type) interface{} {
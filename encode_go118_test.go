@@ -0,0 +1,22 @@
+//go:build go1.18
+
+package easycsv
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+)
+
+func TestConverterNetip(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("2001:db8::1,10.0.0.0/8\n"))
+	var e struct {
+		Addr   netip.Addr   `index:"0"`
+		Prefix netip.Prefix `index:"1"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Addr", e.Addr.String(), "2001:db8::1")
+	noDiff(t, "Prefix", e.Prefix.String(), "10.0.0.0/8")
+}
@@ -0,0 +1,26 @@
+//go:build go1.22
+
+package easycsv
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+)
+
+func TestConverterSQLNullGeneric(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("Tokyo,10\n,"))
+	type entry struct {
+		City  sql.Null[string] `index:"0"`
+		Count sql.Null[uint16] `index:"1"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{{
+		City:  sql.Null[string]{V: "Tokyo", Valid: true},
+		Count: sql.Null[uint16]{V: 10, Valid: true},
+	}, {}}
+	noDiff(t, "ReadAll()", got, want)
+}
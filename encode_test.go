@@ -2,8 +2,18 @@ package easycsv
 
 import (
 	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestConverterInt(t *testing.T) {
@@ -42,3 +52,667 @@ func TestConverterInvalidWithStruct(t *testing.T) {
 		t.Errorf("Unexpected error: %v", err)
 	}
 }
+
+func TestConverterTime(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("2017-01-02T03:04:05+09:00,2017/01/02,Jan 2 03:04:05"))
+	var e struct {
+		Default time.Time `index:"0"`
+		Date    time.Time `index:"1" layout:"2006/01/02"`
+		Stamp   time.Time `index:"2" layout:"Stamp"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Default", e.Default.Format(time.RFC3339), "2017-01-02T03:04:05+09:00")
+	noDiff(t, "Date", e.Date, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC))
+	noDiff(t, "Stamp", e.Stamp, time.Date(0, 1, 2, 3, 4, 5, 0, time.UTC))
+}
+
+func TestConverterTimeInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("2017-01-02"))
+	var e struct {
+		Time time.Time `index:"0"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "cannot parse") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterDuration(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1.5s,1h2m,1.5,250"))
+	var e struct {
+		Default  time.Duration `index:"0"`
+		Duration time.Duration `index:"1" enc:"duration"`
+		Seconds  time.Duration `index:"2" enc:"seconds"`
+		Millis   time.Duration `index:"3" enc:"millis"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Default", e.Default, 1500*time.Millisecond)
+	noDiff(t, "Duration", e.Duration, time.Hour+2*time.Minute)
+	noDiff(t, "Seconds", e.Seconds, 1500*time.Millisecond)
+	noDiff(t, "Millis", e.Millis, 250*time.Millisecond)
+}
+
+func TestConverterUnixTime(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1500000000,1500000000123,1500000000123456789,1500000000123"))
+	var e struct {
+		Unix   time.Time `index:"0" enc:"unix"`
+		UnixMS time.Time `index:"1" enc:"unixms"`
+		UnixNS time.Time `index:"2" enc:"unixns"`
+		Int    int64     `index:"3" enc:"unixms"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Unix", e.Unix, time.Unix(1500000000, 0).UTC())
+	noDiff(t, "UnixMS", e.UnixMS, time.Unix(1500000000, 123000000).UTC())
+	noDiff(t, "UnixNS", e.UnixNS, time.Unix(1500000000, 123456789).UTC())
+	noDiff(t, "Int", e.Int, int64(1500000000123))
+}
+
+func TestConverterUnixTimeUnsupportedType(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1500000000"))
+	var e struct {
+		Unix string `index:"0" enc:"unix"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "Encoding \"unix\" does not support string") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterPointer(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("10,,2017-01-02,ff\n,1.5,,"))
+	type entry struct {
+		Int   *int       `index:"0"`
+		Float *float64   `index:"1"`
+		Date  *time.Time `index:"2" layout:"2006-01-02"`
+		Hex   *int       `index:"3" enc:"hex"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	i, f, h := 10, 1.5, 255
+	d := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	want := []entry{{Int: &i, Date: &d, Hex: &h}, {Float: &f}}
+	noDiff(t, "ReadAll() with pointers", got, want)
+}
+
+func TestConverterPointerInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("hello"))
+	var row []*int
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "parsing \"hello\"") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type csvUpper string
+
+func (u *csvUpper) UnmarshalCSV(s string) error {
+	if s == "" {
+		return errors.New("empty csvUpper")
+	}
+	*u = csvUpper(strings.ToUpper(s))
+	return nil
+}
+
+type textPair struct {
+	Key, Value string
+}
+
+func (p *textPair) UnmarshalText(b []byte) error {
+	kv := strings.SplitN(string(b), "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid pair: %q", b)
+	}
+	p.Key, p.Value = kv[0], kv[1]
+	return nil
+}
+
+func TestConverterUnmarshaler(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("hello,a=b,c=d\nworld,e=f,"))
+	type entry struct {
+		Upper   csvUpper  `index:"0"`
+		Pair    textPair  `index:"1"`
+		PairPtr *textPair `index:"2"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{
+		{Upper: "HELLO", Pair: textPair{"a", "b"}, PairPtr: &textPair{"c", "d"}},
+		{Upper: "WORLD", Pair: textPair{"e", "f"}},
+	}
+	noDiff(t, "ReadAll() with Unmarshaler", got, want)
+}
+
+func TestConverterUnmarshalerError(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("a=b,c"))
+	var row []textPair
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "invalid pair: \"c\"" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type celsius float64
+type userID int64
+type label string
+type enabled bool
+
+func TestConverterNamedTypes(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("36.5,42,ff,hello,true,18446744073709551615"))
+	var e struct {
+		Temp    celsius `index:"0"`
+		ID      userID  `index:"1"`
+		Hex     userID  `index:"2" enc:"hex"`
+		Label   label   `index:"3"`
+		Enabled enabled `index:"4"`
+		Max     uint64  `index:"5"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Temp", e.Temp, celsius(36.5))
+	noDiff(t, "ID", e.ID, userID(42))
+	noDiff(t, "Hex", e.Hex, userID(255))
+	noDiff(t, "Label", e.Label, label("hello"))
+	noDiff(t, "Enabled", e.Enabled, enabled(true))
+	noDiff(t, "Max", e.Max, uint64(18446744073709551615))
+}
+
+type shape interface {
+	area() float64
+}
+
+type square struct{ Side float64 }
+
+func (s square) area() float64 { return s.Side * s.Side }
+
+type circle struct{ Radius float64 }
+
+func (c circle) area() float64 { return c.Radius * c.Radius * 3 }
+
+func TestConverterInterfaceTypeDecoder(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("square:2,circle:1"), Option{
+		TypeDecoders: map[reflect.Type]interface{}{
+			reflect.TypeOf((*shape)(nil)).Elem(): func(s string) (shape, error) {
+				kv := strings.SplitN(s, ":", 2)
+				f, err := strconv.ParseFloat(kv[1], 64)
+				if kv[0] == "square" {
+					return square{f}, err
+				}
+				return circle{f}, err
+			},
+		},
+	})
+	var e struct {
+		Square square `index:"0"`
+		Circle circle `index:"1"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Square", e.Square, square{2})
+	noDiff(t, "Circle", e.Circle, circle{1})
+
+	r = NewReader(bytes.NewBufferString("circle:1"), Option{
+		TypeDecoders: map[reflect.Type]interface{}{
+			reflect.TypeOf((*shape)(nil)).Elem(): func(s string) (shape, error) {
+				return circle{1}, nil
+			},
+		},
+	})
+	var row []square
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "The decoder for easycsv.shape returned easycsv.circle, but easycsv.square is expected" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type named int
+
+func (n named) String() string { return strconv.Itoa(int(n)) }
+
+func TestConverterInterfaceTypeDecoderPrecedence(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("2017-01-02T03:04:05Z,12345678901234567890,10.0.0.1,42"), Option{
+		TypeDecoders: map[reflect.Type]interface{}{
+			reflect.TypeOf((*fmt.Stringer)(nil)).Elem(): func(s string) (fmt.Stringer, error) {
+				return nil, errors.New("Stringer decoder must not be used")
+			},
+		},
+	})
+	var e struct {
+		Time  time.Time `index:"0"`
+		Big   big.Int   `index:"1"`
+		IP    net.IP    `index:"2"`
+		Named named     `index:"3"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Time", e.Time, time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC))
+	noDiff(t, "Big", e.Big.String(), "12345678901234567890")
+	noDiff(t, "IP", e.IP.String(), "10.0.0.1")
+	noDiff(t, "Named", e.Named, named(42))
+}
+
+func TestConverterBig(t *testing.T) {
+	r := NewReader(bytes.NewBufferString(
+		"123456789012345678901234567890,1234567890123456789012345.123456789,1/3,ffffffffffffffffffffffff,\n"))
+	var e struct {
+		Int   *big.Int   `index:"0"`
+		Float *big.Float `index:"1"`
+		Rat   *big.Rat   `index:"2"`
+		Hex   *big.Int   `index:"3" enc:"hex"`
+		Null  *big.Int   `index:"4"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Int", e.Int.String(), "123456789012345678901234567890")
+	noDiff(t, "Float", e.Float.Text('f', 9), "1234567890123456789012345.123456789")
+	noDiff(t, "Rat", e.Rat.String(), "1/3")
+	noDiff(t, "Hex", e.Hex.Text(16), "ffffffffffffffffffffffff")
+	if e.Null != nil {
+		t.Errorf("Null must be nil but got %v", e.Null)
+	}
+}
+
+func TestConverterBigInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("12x"))
+	var row []*big.Int
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "Failed to parse \"12x\" as big.Int" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterBytes(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("raw,aGVsbG8=,_-8=,cafe,123e4567-e89b-12d3-a456-426614174000"))
+	var e struct {
+		Raw       []byte   `index:"0"`
+		Base64    []byte   `index:"1" enc:"base64"`
+		Base64URL []byte   `index:"2" enc:"base64url"`
+		Hex       [2]byte  `index:"3" enc:"hexbytes"`
+		UUID      [16]byte `index:"4" enc:"hexbytes"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Raw", e.Raw, []byte("raw"))
+	noDiff(t, "Base64", e.Base64, []byte("hello"))
+	noDiff(t, "Base64URL", e.Base64URL, []byte{0xff, 0xef})
+	noDiff(t, "Hex", e.Hex, [2]byte{0xca, 0xfe})
+	noDiff(t, "UUID", e.UUID, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00})
+}
+
+func TestConverterBytesInvalidLength(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("cafe00"))
+	var e struct {
+		Hex [2]byte `index:"0" enc:"hexbytes"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "[2]uint8 requires 2 bytes, but got 3 bytes" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterJSON(t *testing.T) {
+	r := NewReader(bytes.NewBufferString(`id,attrs,tags,pair
+1,"{""color"":""red"",""size"":3}","[""a"",""b""]","{""City"":""Tokyo"",""Zip"":""100""}"
+2,,null,`))
+	type entry struct {
+		ID    int                    `name:"id"`
+		Attrs map[string]interface{} `name:"attrs" enc:"json"`
+		Tags  []string               `name:"tags" enc:"json"`
+		Pair  *address               `name:"pair" enc:"json"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{
+		{ID: 1, Attrs: map[string]interface{}{"color": "red", "size": 3.0}, Tags: []string{"a", "b"}, Pair: &address{City: "Tokyo", Zip: "100"}},
+		{ID: 2},
+	}
+	noDiff(t, "ReadAll() with json", got, want)
+}
+
+func TestConverterJSONInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString(`"{""a"":"`))
+	var e struct {
+		Attrs map[string]int `index:"0" enc:"json"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "unexpected end of JSON input" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterNetworkTypes(t *testing.T) {
+	r := NewReader(bytes.NewBufferString(
+		"192.168.0.1,00:00:5e:00:53:01,https://example.com/a?b=c,Alice <alice@example.com>\n"))
+	var e struct {
+		IP   net.IP           `index:"0"`
+		MAC  net.HardwareAddr `index:"1"`
+		URL  *url.URL         `index:"2"`
+		Mail mail.Address     `index:"3"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "IP", e.IP.String(), "192.168.0.1")
+	noDiff(t, "MAC", e.MAC.String(), "00:00:5e:00:53:01")
+	noDiff(t, "URL", e.URL.String(), "https://example.com/a?b=c")
+	noDiff(t, "Mail", e.Mail, mail.Address{Name: "Alice", Address: "alice@example.com"})
+}
+
+func TestConverterNetworkTypesInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("192.168.0.256"))
+	var row []net.IP
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "invalid IP address") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterPercentCurrencyByteSize(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("12.5%\t$1,200.00\t(300)\tUSD 42\t¥1,000\t10MB\t1.5GiB\t512"), Option{Comma: '\t'})
+	var e struct {
+		Percent  float64 `index:"0" enc:"percent"`
+		Dollar   float64 `index:"1" enc:"currency"`
+		Negative float32 `index:"2" enc:"currency"`
+		Code     float64 `index:"3" enc:"currency"`
+		Yen      float64 `index:"4" enc:"currency"`
+		MB       int64   `index:"5" enc:"bytesize"`
+		GiB      uint64  `index:"6" enc:"bytesize"`
+		Bytes    int     `index:"7" enc:"bytesize"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Percent", e.Percent, 0.125)
+	noDiff(t, "Dollar", e.Dollar, 1200.0)
+	noDiff(t, "Negative", e.Negative, float32(-300))
+	noDiff(t, "Code", e.Code, 42.0)
+	noDiff(t, "Yen", e.Yen, 1000.0)
+	noDiff(t, "MB", e.MB, int64(10000000))
+	noDiff(t, "GiB", e.GiB, uint64(1610612736))
+	noDiff(t, "Bytes", e.Bytes, 512)
+}
+
+func TestConverterCurrencyErrors(t *testing.T) {
+	for _, in := range []string{"1.5e3", "12abc34", "$12 USD 34", "1,200$5"} {
+		r := NewReader(bytes.NewBufferString(in), Option{Comma: ';'})
+		var e struct {
+			Amount float64 `index:"0" enc:"currency"`
+		}
+		if r.Read(&e) {
+			t.Errorf("Read returned true unexpectedly for %q: %v", in, e.Amount)
+		}
+		if err := r.Done(); err == nil || err.Error() != fmt.Sprintf("Failed to parse %q as currency", in) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+	for _, in := range []string{"$1.5", "1.23 €"} {
+		r := NewReader(bytes.NewBufferString(in), Option{Comma: ';', ThousandsSeparator: ".", DecimalSeparator: ","})
+		var e struct {
+			Amount float64 `index:"0" enc:"currency"`
+		}
+		if r.Read(&e) {
+			t.Errorf("Read returned true unexpectedly for %q: %v", in, e.Amount)
+		}
+		if err := r.Done(); err == nil || err.Error() != fmt.Sprintf("Failed to parse %q as currency", in) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+	r := NewReader(bytes.NewBufferString("-$5;1.234,5 €;+42 JPY"), Option{Comma: ';', ThousandsSeparator: ".", DecimalSeparator: ","})
+	var row struct {
+		A float64 `index:"0" enc:"currency"`
+		B float64 `index:"1" enc:"currency"`
+		C float64 `index:"2" enc:"currency"`
+	}
+	if !r.Read(&row) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "A", row.A, -5.0)
+	noDiff(t, "B", row.B, 1234.5)
+	noDiff(t, "C", row.C, 42.0)
+}
+
+func TestConverterByteSizeErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{input: "10XB", err: "Unknown unit of byte size in \"10XB\""},
+		{input: "1PB", err: "\"1PB\" overflows int32"},
+		{input: "1..5MB", err: "Failed to parse \"1..5MB\" as byte size"},
+	}
+	for _, test := range tests {
+		r := NewReader(bytes.NewBufferString(test.input))
+		var e struct {
+			Size int32 `index:"0" enc:"bytesize"`
+		}
+		if r.Read(&e) {
+			t.Errorf("Read returned true unexpectedly for %q", test.input)
+		}
+		if err := r.Done(); err == nil || err.Error() != test.err {
+			t.Errorf("Unexpected error for %q: %v", test.input, err)
+		}
+	}
+}
+
+func TestConverterInfer(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("007,1.5,true,2017-01-02T03:04:05Z,hello,\n"))
+	var got []interface{}
+	if !r.Read(&got) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	want := []interface{}{int64(7), 1.5, true, time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), "hello", nil}
+	noDiff(t, "Read()", got, want)
+}
+
+func TestConverterInferKeepsWords(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("F,T,t,f,NaN,inf,-Inf,TRUE,yes,1e3"), Option{TrueValues: []string{"yes"}})
+	var got []interface{}
+	if !r.Read(&got) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	want := []interface{}{"F", "T", "t", "f", "NaN", "inf", "-Inf", true, true, 1000.0}
+	noDiff(t, "Read()", got, want)
+}
+
+func TestConverterInferWithInferTypes(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1,2.5,2017-01-02"), Option{
+		TimeLayout: "2006-01-02",
+		InferTypes: []reflect.Type{reflect.TypeOf(float64(0)), reflect.TypeOf(time.Time{})},
+	})
+	var e struct {
+		A interface{}  `index:"0"`
+		B interface{}  `index:"1"`
+		C *interface{} `index:"2"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "A", e.A, 1.0)
+	noDiff(t, "B", e.B, 2.5)
+	noDiff(t, "C", *e.C, interface{}(time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestConverterInferInvalidInferTypes(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1"), Option{
+		InferTypes: []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()},
+	})
+	var row []interface{}
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != "InferTypes must not contain interface types, but got error" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestConverterSQLNullTypes(t *testing.T) {
+	f := bytes.NewBufferString("Alice,42,1.5,true,2017-01-02,7\nNULL,NULL,NULL,NULL,NULL,NULL")
+	r := NewReader(f, Option{
+		NullValues: []string{"NULL"},
+		NullPolicy: NullError,
+		TimeLayout: "2006-01-02",
+	})
+	type entry struct {
+		String sql.NullString  `index:"0"`
+		Int    sql.NullInt64   `index:"1"`
+		Float  sql.NullFloat64 `index:"2"`
+		Bool   sql.NullBool    `index:"3"`
+		Time   sql.NullTime    `index:"4"`
+		Int32  *sql.NullInt32  `index:"5"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{{
+		String: sql.NullString{String: "Alice", Valid: true},
+		Int:    sql.NullInt64{Int64: 42, Valid: true},
+		Float:  sql.NullFloat64{Float64: 1.5, Valid: true},
+		Bool:   sql.NullBool{Bool: true, Valid: true},
+		Time:   sql.NullTime{Time: time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+		Int32:  &sql.NullInt32{Int32: 7, Valid: true},
+	}, {}}
+	noDiff(t, "ReadAll()", got, want)
+}
+
+func TestConverterSQLNullTypesInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("x"))
+	var row []sql.NullInt64
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "parsing \"x\"") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type status int
+
+const (
+	statusActive status = iota + 1
+	statusInactive
+	statusDeleted
+)
+
+var statusEnum = map[reflect.Type]map[string]interface{}{
+	reflect.TypeOf(status(0)): {
+		"active":   statusActive,
+		"inactive": statusInactive,
+		"deleted":  statusDeleted,
+		"unknown":  0,
+	},
+}
+
+func TestConverterEnum(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("active,Deleted,INACTIVE,unknown,"), Option{
+		Enums:               statusEnum,
+		EnumCaseInsensitive: true,
+	})
+	var e struct {
+		A status  `index:"0"`
+		B status  `index:"1"`
+		C status  `index:"2"`
+		D status  `index:"3"`
+		E *status `index:"4"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "A", e.A, statusActive)
+	noDiff(t, "B", e.B, statusDeleted)
+	noDiff(t, "C", e.C, statusInactive)
+	noDiff(t, "D", e.D, status(0))
+	if e.E != nil {
+		t.Errorf("E must be nil, but got %v", *e.E)
+	}
+}
+
+func TestConverterEnumInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("Active"), Option{Enums: statusEnum})
+	var row []status
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	want := `"Active" is not a valid easycsv.status: allowed values are active, deleted, inactive, unknown`
+	if err := r.Done(); err == nil || err.Error() != want {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	r = NewReader(bytes.NewBufferString("a"), Option{Enums: map[reflect.Type]map[string]interface{}{
+		reflect.TypeOf(status(0)): {"a": "x"},
+	}})
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	want = `The value of "a" in Enums for easycsv.status must be easycsv.status, but got string`
+	if err := r.Done(); err == nil || err.Error() != want {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	type color uint8
+	type label string
+	tests := []struct {
+		enums map[reflect.Type]map[string]interface{}
+		row   interface{}
+		err   string
+	}{{
+		enums: map[reflect.Type]map[string]interface{}{reflect.TypeOf(status(0)): {"a": 1.9}},
+		row:   &[]status{},
+		err:   `The value of "a" in Enums for easycsv.status must be easycsv.status, but got float64`,
+	}, {
+		enums: map[reflect.Type]map[string]interface{}{reflect.TypeOf(status(0)): {"a": color(1)}},
+		row:   &[]status{},
+		err:   `The value of "a" in Enums for easycsv.status must be easycsv.status, but got easycsv.color`,
+	}, {
+		enums: map[reflect.Type]map[string]interface{}{reflect.TypeOf(color(0)): {"a": 256}},
+		row:   &[]color{},
+		err:   `The value of "a" in Enums for easycsv.color overflows easycsv.color: 256`,
+	}, {
+		enums: map[reflect.Type]map[string]interface{}{reflect.TypeOf(label("")): {"a": label("x")}},
+		row:   &[]label{},
+		err:   `The key of Enums must be an integer type, but got easycsv.label`,
+	}}
+	for _, test := range tests {
+		r := NewReader(bytes.NewBufferString("a"), Option{Enums: test.enums})
+		if r.Read(test.row) {
+			t.Errorf("Read returned true unexpectedly for %q", test.err)
+		}
+		if err := r.Done(); err == nil || err.Error() != test.err {
+			t.Errorf("Unexpected error: %v; want %q", err, test.err)
+		}
+	}
+}
@@ -2,7 +2,24 @@ package easycsv
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// NullPolicy specifies how null values (see Option.NullValues) are decoded into non-pointer fields.
+type NullPolicy int
+
+const (
+	// NullParse passes null values to the decoders of fields as they are.
+	// For example, an empty string is decoded into "" in string fields, but it is an error in int fields.
+	NullParse NullPolicy = iota
+	// NullZero sets fields to the zero values if the values in CSV are null.
+	NullZero
+	// NullError reports an error if the values in CSV are null.
+	NullError
 )
 
 // Option specifies the spec of Reader.
@@ -22,11 +39,66 @@ type Option struct {
 	Decoders map[string]interface{}
 	// Custom decoders to parse specific types.
 	TypeDecoders map[reflect.Type]interface{}
+	// Enums maps types to the tables from names in CSV to values (e.g. {"active": StatusActive}).
+	// Fields of the types are decoded by looking up the tables. The types must be integer types
+	// and the values must be the types or untyped integer constants (int).
+	Enums map[reflect.Type]map[string]interface{}
+	// If EnumCaseInsensitive is true, names in Enums are compared case-insensitively.
+	EnumCaseInsensitive bool
+	// TimeLayout is the default layout to parse time.Time fields (e.g. "2006-01-02").
+	// Predefined layout names in the time package like "RFC3339" are also accepted.
+	// If empty, time.RFC3339 is used. The layout tag of a field overrides this.
+	TimeLayout string
+	// Location is the time zone used to interpret times without time zone information.
+	// If nil, UTC is used.
+	Location *time.Location
+	// NullValues is the list of values regarded as null (e.g. "NA", "NULL").
+	// Pointer fields are set to nil if the values in CSV are null.
+	// If nil, only an empty string is regarded as null.
+	NullValues []string
+	// NullPolicy specifies how null values are decoded into non-pointer fields.
+	// If NullParse (default), null values are passed to the decoders of the fields as they are.
+	// default tags of fields (e.g. `default:"0"`) take precedence over this.
+	NullPolicy NullPolicy
+	// TrueValues and FalseValues are the words regarded as true and false in bool fields (e.g. "yes" and "no").
+	// The words are compared case-insensitively. Inputs which match neither are parsed with strconv.ParseBool.
+	// true and false tags of fields (e.g. `true:"yes,y" false:"no,n"`) override these.
+	TrueValues  []string
+	FalseValues []string
+	// ThousandsSeparator is the separator of digit groups in number fields (e.g. "," for "1,234" or "." for "1.234,56").
+	// Digit groups after the first group must have three digits. ThousandsSeparator must be different from
+	// DecimalSeparator, which is "." by default. The thousands tag of a field overrides this.
+	ThousandsSeparator string
+	// DecimalSeparator is the decimal separator in float fields (e.g. "," for "1.234,56"). If empty, "." is used.
+	// The decimal tag of a field overrides this.
+	DecimalSeparator string
+	// InferTypes is the list of types tried in order to decode values into interface{} fields and slices.
+	// Values are decoded into the first type that succeeds and into string if no type succeeds. Null values are decoded into nil.
+	// If nil, int64, float64, bool and time.Time are tried in this order.
+	// Integers must be decimals, bools must be "true", "false" or TrueValues and FalseValues, and floats must be finite.
+	InferTypes []reflect.Type
+	// TrimSpace, if true, removes leading and trailing white spaces in values before they are decoded.
+	// The trim tag of a field (e.g. `trim:"true"`) overrides this.
+	TrimSpace bool
+	// CollapseSpace, if true, replaces consecutive white spaces in values with a single space.
+	// The collapse tag of a field (e.g. `collapse:"true"`) overrides this.
+	CollapseSpace bool
+	// Case converts values to "upper", "lower" or "title" case before they are decoded.
+	// The case tag of a field (e.g. `case:"upper"`) overrides this.
+	Case string
+	// Transform, if not nil, is applied to every value in CSV before the values are trimmed and decoded.
+	// column is the name of the column if the header is used. Otherwise, it is the 0-based index of the column (e.g. "3").
+	Transform func(column, value string) string
 
-	// TODO: Support AutoIndex
+	// If AutoIndex is true, fields without name and index tags are mapped to columns in declaration order.
+	// The i-th field (0-based) in a struct is mapped to the i-th column.
 	AutoIndex bool
-	// TODO: Support AutoName
+	// If AutoName is true, fields without name and index tags are mapped to columns by their names.
+	// The names of fields are converted to the names of columns with NameMapper.
 	AutoName bool
+	// NameMapper converts the names of fields to the names of columns when AutoName is true
+	// (e.g. SnakeCase, KebabCase or strings.ToLower). If nil, the names of fields are used as they are.
+	NameMapper func(string) string
 }
 
 func (a *Option) mergeOption(b Option) {
@@ -42,12 +114,57 @@ func (a *Option) mergeOption(b Option) {
 	if b.AutoName {
 		a.AutoName = true
 	}
+	if b.NameMapper != nil {
+		a.NameMapper = b.NameMapper
+	}
 	if b.LazyQuotes {
 		a.LazyQuotes = b.LazyQuotes
 	}
 	if b.FieldsPerRecord != 0 {
 		a.FieldsPerRecord = b.FieldsPerRecord
 	}
+	if b.TimeLayout != "" {
+		a.TimeLayout = b.TimeLayout
+	}
+	if b.Location != nil {
+		a.Location = b.Location
+	}
+	if b.NullValues != nil {
+		a.NullValues = b.NullValues
+	}
+	if b.NullPolicy != NullParse {
+		a.NullPolicy = b.NullPolicy
+	}
+	if b.TrueValues != nil {
+		a.TrueValues = b.TrueValues
+	}
+	if b.FalseValues != nil {
+		a.FalseValues = b.FalseValues
+	}
+	if b.ThousandsSeparator != "" {
+		a.ThousandsSeparator = b.ThousandsSeparator
+	}
+	if b.DecimalSeparator != "" {
+		a.DecimalSeparator = b.DecimalSeparator
+	}
+	if b.InferTypes != nil {
+		a.InferTypes = b.InferTypes
+	}
+	if b.EnumCaseInsensitive {
+		a.EnumCaseInsensitive = true
+	}
+	if b.TrimSpace {
+		a.TrimSpace = true
+	}
+	if b.CollapseSpace {
+		a.CollapseSpace = true
+	}
+	if b.Case != "" {
+		a.Case = b.Case
+	}
+	if b.Transform != nil {
+		a.Transform = b.Transform
+	}
 	if b.Decoders != nil {
 		if a.Decoders == nil {
 			a.Decoders = make(map[string]interface{})
@@ -64,13 +181,215 @@ func (a *Option) mergeOption(b Option) {
 			a.TypeDecoders[t] = dec
 		}
 	}
+	if b.Enums != nil {
+		if a.Enums == nil {
+			a.Enums = make(map[reflect.Type]map[string]interface{})
+		}
+		for t, enum := range b.Enums {
+			a.Enums[t] = enum
+		}
+	}
+}
+
+// isNull returns true if s is regarded as null.
+func (a *Option) isNull(s string) bool {
+	if a.NullValues == nil {
+		return s == ""
+	}
+	for _, n := range a.NullValues {
+		if s == n {
+			return true
+		}
+	}
+	return false
+}
+
+// numberNormalizer returns a func to convert numbers in the locale specified by ThousandsSeparator and
+// DecimalSeparator to the format accepted by strconv. It returns nil if the separators are not specified.
+// The func reports an error if digit groups are not three digits (e.g. "1,2,3") or if s has "." which is
+// neither the thousands separator nor the decimal separator (e.g. "1.5" when DecimalSeparator is ",").
+func (a *Option) numberNormalizer() func(string) (string, error) {
+	thousands, decimal := a.ThousandsSeparator, a.DecimalSeparator
+	if thousands == "" && (decimal == "" || decimal == ".") {
+		return nil
+	}
+	return func(s string) (string, error) {
+		if thousands != "" && strings.Contains(s, thousands) {
+			if !isGroupedNumber(s, thousands, decimal) {
+				return "", fmt.Errorf("Failed to parse %q: digits must be grouped by three with %q", s, thousands)
+			}
+			s = strings.Replace(s, thousands, "", -1)
+		}
+		if decimal != "" && decimal != "." {
+			if strings.Contains(s, ".") {
+				return "", fmt.Errorf("Failed to parse %q: the decimal separator is %q", s, decimal)
+			}
+			s = strings.Replace(s, decimal, ".", 1)
+		}
+		return s, nil
+	}
+}
+
+// isGroupedNumber returns true if the digits before the decimal separator in s are grouped by thousands correctly
+// (e.g. "1,234,567"). The decimal separator is "." if decimal is empty.
+func isGroupedNumber(s, thousands, decimal string) bool {
+	if decimal == "" {
+		decimal = "."
+	}
+	if i := strings.Index(s, decimal); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimLeft(s, "+-")
+	groups := strings.Split(s, thousands)
+	for i, g := range groups {
+		if i == 0 && (len(g) == 0 || len(g) > 3) || i > 0 && len(g) != 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// validateSeparators returns an error if ThousandsSeparator is the same as the decimal separator.
+func (a *Option) validateSeparators() error {
+	decimal := a.DecimalSeparator
+	if decimal == "" {
+		decimal = "."
+	}
+	if a.ThousandsSeparator == decimal {
+		return fmt.Errorf("ThousandsSeparator %q must be different from the decimal separator %q", a.ThousandsSeparator, decimal)
+	}
+	return nil
+}
+
+// caseConverters maps the values of Case to the funcs to convert the case of values.
+var caseConverters = map[string]func(string) string{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"title": titleCase,
+}
+
+// titleCase converts the first letter of each word in s to upper case and the others to lower case.
+func titleCase(s string) string {
+	rs := []rune(s)
+	for i, r := range rs {
+		if i == 0 || unicode.IsSpace(rs[i-1]) {
+			rs[i] = unicode.ToUpper(r)
+		} else {
+			rs[i] = unicode.ToLower(r)
+		}
+	}
+	return string(rs)
+}
+
+// collapseSpace replaces consecutive white spaces in s with a single space.
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// textTransformer returns a func to trim, collapse and convert the case of values based on
+// TrimSpace, CollapseSpace and Case. It returns nil if no transformation is specified.
+func (a *Option) textTransformer() func(string) string {
+	trim, collapse, conv := a.TrimSpace, a.CollapseSpace, caseConverters[a.Case]
+	if !trim && !collapse && conv == nil {
+		return nil
+	}
+	return func(s string) string {
+		if trim {
+			s = strings.TrimSpace(s)
+		}
+		if collapse {
+			s = collapseSpace(s)
+		}
+		if conv != nil {
+			s = conv(s)
+		}
+		return s
+	}
+}
+
+// validateCase returns an error if Case is not a valid value.
+func (a *Option) validateCase() error {
+	if _, ok := caseConverters[a.Case]; a.Case != "" && !ok {
+		return fmt.Errorf("Case must be upper, lower or title, but got %q", a.Case)
+	}
+	return nil
+}
+
+// columnName returns the name of the column mapped to the field named fieldName when AutoName is true.
+func (a *Option) columnName(fieldName string) string {
+	if a.NameMapper == nil {
+		return fieldName
+	}
+	return a.NameMapper(fieldName)
+}
+
+// splitWords splits a name in CamelCase into words (e.g. "UserID" -> "User", "ID").
+func splitWords(name string) []string {
+	var words []string
+	rs := []rune(name)
+	start := 0
+	for i := 1; i < len(rs); i++ {
+		prev, cur := rs[i-1], rs[i]
+		next := rune(0)
+		if i+1 < len(rs) {
+			next = rs[i+1]
+		}
+		if cur == '_' || cur == '-' {
+			if start < i {
+				words = append(words, string(rs[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && unicode.IsLower(next)) {
+			if start < i {
+				words = append(words, string(rs[start:i]))
+			}
+			start = i
+		}
+	}
+	if start < len(rs) {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// SnakeCase converts a field name to a column name in snake_case (e.g. "UserID" -> "user_id").
+// SnakeCase is intended to be used as Option.NameMapper.
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase converts a field name to a column name in kebab-case (e.g. "UserID" -> "user-id").
+// KebabCase is intended to be used as Option.NameMapper.
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
 }
 
 func (a *Option) validate() error {
 	if a.AutoIndex && a.AutoName {
 		return errors.New("You can not set both AutoIndex and AutoName to easycsv.Reader.")
 	}
-	return nil
+	if err := a.validateSeparators(); err != nil {
+		return err
+	}
+	return a.validateCase()
 }
 
 func mergeOptions(opts []Option) (Option, error) {
@@ -2,7 +2,9 @@ package easycsv
 
 import (
 	"bytes"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -254,3 +256,453 @@ func TestTypeDecodersErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeLayoutAndLocation(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	f := bytes.NewBufferString("2017-01-02,2017-01-02 03:04\n2018-12-31,2018-12-31 23:59")
+	r := NewReader(f, Option{
+		TimeLayout: "DateOnly",
+		Location:   jst,
+	})
+	type entry struct {
+		Date time.Time `index:"0"`
+		Time time.Time `index:"1" layout:"2006-01-02 15:04"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{
+		{Date: time.Date(2017, 1, 2, 0, 0, 0, 0, jst), Time: time.Date(2017, 1, 2, 3, 4, 0, 0, jst)},
+		{Date: time.Date(2018, 12, 31, 0, 0, 0, 0, jst), Time: time.Date(2018, 12, 31, 23, 59, 0, 0, jst)},
+	}
+	noDiff(t, "ReadAll() with TimeLayout and Location", got, want)
+}
+
+func TestTimeLayoutWithSlice(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("2017/01/02,2018/03/04"), Option{
+		TimeLayout: "2006/01/02",
+	})
+	var got []time.Time
+	if !r.Read(&got) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	want := []time.Time{
+		time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+	noDiff(t, "Read() with TimeLayout", got, want)
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestNullValuesWithPointer(t *testing.T) {
+	f := bytes.NewBufferString("1:2,NA\nNULL,3:4")
+	r := NewReader(f, Option{
+		NullValues: []string{"NA", "NULL"},
+		TypeDecoders: map[reflect.Type]interface{}{
+			reflect.TypeOf(point{}): func(s string) (p point, err error) {
+				_, err = fmt.Sscanf(s, "%d:%d", &p.X, &p.Y)
+				return
+			},
+		},
+	})
+	var got [][]*point
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := [][]*point{{{X: 1, Y: 2}, nil}, {nil, {X: 3, Y: 4}}}
+	noDiff(t, "ReadAll() with NullValues", got, want)
+}
+
+func TestCustomDecoderWithPointer(t *testing.T) {
+	f := bytes.NewBufferString("hello,\n,world")
+	r := NewReader(f, Option{
+		Decoders: map[string]interface{}{
+			"custom": func(s string) (string, error) { return "[" + s + "]", nil },
+		},
+	})
+	type entry struct {
+		S0 *string `index:"0" enc:"custom"`
+		S1 *string `index:"1" enc:"custom"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	hello, world := "[hello]", "[world]"
+	want := []entry{{S0: &hello}, {S1: &world}}
+	noDiff(t, "ReadAll() with custom decoders", got, want)
+}
+
+func TestAutoIndex(t *testing.T) {
+	f := bytes.NewBufferString("Alice,10,x\nBob,20,y")
+	r := NewReader(f, Option{
+		AutoIndex: true,
+	})
+	type entry struct {
+		Name   string
+		Age    int
+		Ignore string `name:"-"`
+		Tag    string `index:"2"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	want := []entry{{Name: "Alice", Age: 10, Tag: "x"}, {Name: "Bob", Age: 20, Tag: "y"}}
+	noDiff(t, "ReadAll() with AutoIndex", got, want)
+}
+
+func TestAutoName(t *testing.T) {
+	tests := []struct {
+		header string
+		mapper func(string) string
+	}{
+		{header: "UserID,FullName,Zip", mapper: nil},
+		{header: "user_id,full_name,zip", mapper: SnakeCase},
+		{header: "user-id,full-name,zip", mapper: KebabCase},
+		{header: "userid,fullname,zip", mapper: strings.ToLower},
+	}
+	type addr struct {
+		Zip string
+	}
+	type entry struct {
+		UserID   int
+		FullName string
+		addr
+	}
+	for _, test := range tests {
+		r := NewReader(bytes.NewBufferString(test.header+"\n1,Alice,100"), Option{
+			AutoName:   true,
+			NameMapper: test.mapper,
+		})
+		var got []entry
+		if err := r.ReadAll(&got); err != nil {
+			t.Errorf("Failed to read %q: %v", test.header, err)
+			continue
+		}
+		want := []entry{{UserID: 1, FullName: "Alice", addr: addr{Zip: "100"}}}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(entry{})); diff != "" {
+			t.Errorf("ReadAll() with %q mismatch (-want +got):\n%s", test.header, diff)
+		}
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":         "name",
+		"UserID":       "user_id",
+		"HTTPRequest":  "http_request",
+		"Address2Line": "address2_line",
+		"already_done": "already_done",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q; want %q", in, got, want)
+		}
+	}
+	if got, want := KebabCase("HTTPRequestID"), "http-request-id"; got != want {
+		t.Errorf("KebabCase(%q) = %q; want %q", "HTTPRequestID", got, want)
+	}
+}
+
+func TestBoolValues(t *testing.T) {
+	f := bytes.NewBufferString("Yes,✓,はい,true\nno,,いいえ,0")
+	r := NewReader(f, Option{
+		TrueValues:  []string{"yes", "y", "✓"},
+		FalseValues: []string{"no", "n", ""},
+	})
+	type entry struct {
+		Yes    bool  `index:"0"`
+		Check  bool  `index:"1"`
+		Hai    bool  `index:"2" true:"はい" false:"いいえ"`
+		Strict *bool `index:"3"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	tr, fa := true, false
+	want := []entry{
+		{Yes: true, Check: true, Hai: true, Strict: &tr},
+		{Yes: false, Check: false, Hai: false, Strict: &fa},
+	}
+	noDiff(t, "ReadAll() with TrueValues and FalseValues", got, want)
+}
+
+func TestBoolValuesInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("maybe"), Option{
+		TrueValues:  []string{"yes"},
+		FalseValues: []string{"no"},
+	})
+	var row []bool
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), "parsing \"maybe\"") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type yn bool
+
+func (b *yn) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "oui":
+		*b = true
+	case "non":
+		*b = false
+	default:
+		return fmt.Errorf("invalid yn %q", text)
+	}
+	return nil
+}
+
+func TestBoolValuesUnmarshaler(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("oui,yes"), Option{TrueValues: []string{"yes"}})
+	var e struct {
+		YN   yn   `index:"0"`
+		Bool bool `index:"1"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "YN", e.YN, yn(true))
+	noDiff(t, "Bool", e.Bool, true)
+}
+
+func TestNumberSeparators(t *testing.T) {
+	f := bytes.NewBufferString("1.234,56;1.234.567;1 234,5;1,234.5;007")
+	r := NewReader(f, Option{
+		Comma:              ';',
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+	})
+	var e struct {
+		Float   float64  `index:"0"`
+		Int     int      `index:"1"`
+		Space   float32  `index:"2" thousands:" "`
+		English *float64 `index:"3" thousands:"," decimal:"."`
+		Deci    int      `index:"4" enc:"deci"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Float", e.Float, 1234.56)
+	noDiff(t, "Int", e.Int, 1234567)
+	noDiff(t, "Space", e.Space, float32(1234.5))
+	noDiff(t, "English", *e.English, 1234.5)
+	noDiff(t, "Deci", e.Deci, 7)
+}
+
+func TestNullPolicyZero(t *testing.T) {
+	f := bytes.NewBufferString("1,NA,2.5,\n,-,NA,x")
+	r := NewReader(f, Option{
+		NullValues: []string{"", "NA", "-"},
+		NullPolicy: NullZero,
+	})
+	type entry struct {
+		Int    int      `index:"0"`
+		Float  float64  `index:"1"`
+		Ptr    *float64 `index:"2"`
+		String string   `index:"3"`
+	}
+	var got []entry
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	f25 := 2.5
+	want := []entry{{Int: 1, Ptr: &f25}, {String: "x"}}
+	noDiff(t, "ReadAll() with NullZero", got, want)
+}
+
+func TestNullPolicyError(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1,NULL"), Option{
+		NullValues: []string{"NULL"},
+		NullPolicy: NullError,
+	})
+	var e struct {
+		A int `index:"0"`
+		B int `index:"1"`
+	}
+	if r.Read(&e) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != `B must not be null, but got "NULL"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestNullPolicyWithSlice(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1,,3"), Option{NullPolicy: NullZero})
+	var got [][]int
+	if err := r.ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	noDiff(t, "ReadAll() with NullZero", got, [][]int{{1, 0, 3}})
+}
+
+func TestTextTransforms(t *testing.T) {
+	f := bytes.NewBufferString("id,city,code,note\n 42 ,  new   YORK ,jp , keep  this \n")
+	r := NewReader(f, Option{TrimSpace: true})
+	var e struct {
+		ID   int    `name:"id"`
+		City string `name:"city" collapse:"true" case:"title"`
+		Code string `name:"code" case:"upper"`
+		Note string `name:"note" trim:"false"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "ID", e.ID, 42)
+	noDiff(t, "City", e.City, "New York")
+	noDiff(t, "Code", e.Code, "JP")
+	noDiff(t, "Note", e.Note, " keep  this ")
+}
+
+func TestTransformHook(t *testing.T) {
+	var columns []string
+	transform := func(column, value string) string {
+		columns = append(columns, column)
+		return strings.TrimPrefix(value, "#")
+	}
+	r := NewReader(bytes.NewBufferString("a,b\n#1,#2 \n"), Option{Transform: transform, TrimSpace: true})
+	var e struct {
+		A int `name:"a"`
+		B int `name:"b"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "A", e.A, 1)
+	noDiff(t, "B", e.B, 2)
+	sort.Strings(columns)
+	noDiff(t, "columns", columns, []string{"a", "b"})
+
+	columns = nil
+	r = NewReader(bytes.NewBufferString("#1,#2 \n"), Option{Transform: transform, TrimSpace: true})
+	var row []int
+	if !r.Read(&row) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "row", row, []int{1, 2})
+	noDiff(t, "columns", columns, []string{"0", "1"})
+}
+
+func TestTextTransformSepFields(t *testing.T) {
+	type size struct {
+		W    int    `name:"w"`
+		H    int    `name:"h"`
+		Unit string `name:"unit" case:"lower"`
+	}
+	r := NewReader(bytes.NewBufferString("1 ; 2, 10 x 20 x CM,a ; b"), Option{TrimSpace: true})
+	var e struct {
+		Ints  []int    `index:"0" sep:";"`
+		Size  size     `index:"1" sep:"x"`
+		Codes []string `index:"2" sep:";" case:"upper"`
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Ints", e.Ints, []int{1, 2})
+	noDiff(t, "Size", e.Size, size{W: 10, H: 20, Unit: "cm"})
+	noDiff(t, "Codes", e.Codes, []string{"A", "B"})
+}
+
+func TestInvalidCase(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("a"), Option{Case: "camel"})
+	var row []string
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != `Case must be upper, lower or title, but got "camel"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		A string `index:"0" case:"snake"`
+	}{}))
+	if err == nil || err.Error() != `Invalid case tag of field A: "snake"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err = newDecoder(Option{}, reflect.TypeOf(struct {
+		A struct {
+			X string
+			Y string `case:"bogus"`
+		} `index:"0" sep:";"`
+	}{}))
+	if err == nil || err.Error() != `Invalid case tag of field A.Y: "bogus"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestAutoIndexWithIndexRange(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1,2,3,4"), Option{AutoIndex: true})
+	var e struct {
+		A []int `index:"0-1"`
+		B int
+		C int
+	}
+	if !r.Read(&e) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "A", e.A, []int{1, 2})
+	noDiff(t, "B", e.B, 3)
+	noDiff(t, "C", e.C, 4)
+}
+
+func TestNumberSeparatorsInvalid(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("1.5"), Option{ThousandsSeparator: "."})
+	var row []float64
+	if r.Read(&row) {
+		t.Error("Read returned true unexpectedly")
+	}
+	if err := r.Done(); err == nil || err.Error() != `ThousandsSeparator "." must be different from the decimal separator "."` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		F float64 `index:"0" thousands:"," decimal:","`
+	}{}))
+	if err == nil || err.Error() != `Invalid separators of field F: ThousandsSeparator "," must be different from the decimal separator ","` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestNumberSeparatorsGrouping(t *testing.T) {
+	for _, in := range []string{"1,2,3,4", "1234,567", ",123", "12,34.5"} {
+		r := NewReader(bytes.NewBufferString(in), Option{Comma: ';', ThousandsSeparator: ","})
+		var row []float64
+		if r.Read(&row) {
+			t.Errorf("Read returned true unexpectedly for %q: %v", in, row)
+		}
+		if err := r.Done(); err == nil || !strings.Contains(err.Error(), fmt.Sprintf(`Failed to parse %q: digits must be grouped by three with ","`, in)) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+	for _, in := range []string{"1.5", "1.23"} {
+		r := NewReader(bytes.NewBufferString(in), Option{ThousandsSeparator: ".", DecimalSeparator: ","})
+		var row []float64
+		if r.Read(&row) {
+			t.Errorf("Read returned true unexpectedly for %q: %v", in, row)
+		}
+		if err := r.Done(); err == nil || !strings.Contains(err.Error(), fmt.Sprintf(`Failed to parse %q: digits must be grouped by three with "."`, in)) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+	r := NewReader(bytes.NewBufferString("1.5"), Option{DecimalSeparator: ","})
+	var f []float64
+	if r.Read(&f) {
+		t.Errorf("Read returned true unexpectedly: %v", f)
+	}
+	if err := r.Done(); err == nil || !strings.Contains(err.Error(), `Failed to parse "1.5": the decimal separator is ","`) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	r = NewReader(bytes.NewBufferString("-1,234,567.5;12"), Option{Comma: ';', ThousandsSeparator: ","})
+	var row []float64
+	if !r.Read(&row) {
+		t.Fatalf("Read returned false unexpectedly: %v", r.Done())
+	}
+	noDiff(t, "Read()", row, []float64{-1234567.5, 12})
+}
@@ -0,0 +1,181 @@
+package easycsv
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError is the error reported when a value in CSV does not satisfy the validation tags of a field.
+type ValidationError struct {
+	// Field is the name of the field (e.g. "Address.City").
+	Field string
+	// Column is the name of the column if the header is used. Otherwise, it is the 0-based index of the column.
+	Column string
+	// Line is the 1-based line number of the value.
+	Line int
+	// Value is the value in CSV.
+	Value string
+	// Message describes the rule which the value does not satisfy (e.g. "must be at least 1").
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid value %q for field %s (column %s, line %d): %s", e.Value, e.Field, e.Column, e.Line, e.Message)
+}
+
+// fieldValidator validates values decoded into a field with the validation tags of the field.
+//
+//   - required:"true" requires non-null values.
+//   - min and max are the bounds of numbers or the bounds of the lengths of strings, slices and maps.
+//   - pattern is a regular expression which values must match.
+//   - oneof is the comma-separated list of allowed values.
+//
+// Null values are not validated except for required. Fields to which multiple columns are mapped
+// are validated column by column.
+type fieldValidator struct {
+	required bool
+	min, max *float64
+	pattern  *regexp.Regexp
+	oneof    []string
+}
+
+// newFieldValidator returns a validator for field. It returns nil if field has no validation tag.
+func newFieldValidator(field reflect.StructField) (*fieldValidator, error) {
+	tag := field.Tag
+	v := &fieldValidator{required: tag.Get("required") == "true"}
+	found := v.required
+	for _, b := range []struct {
+		name string
+		dst  **float64
+	}{{"min", &v.min}, {"max", &v.max}} {
+		s, ok := tag.Lookup(b.name)
+		if !ok {
+			continue
+		}
+		if !isRangeValidatable(field.Type) {
+			return nil, fmt.Errorf("%s is not supported for %v", b.name, field.Type)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s %q", b.name, s)
+		}
+		*b.dst = &f
+		found = true
+	}
+	if p, ok := tag.Lookup("pattern"); ok {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse pattern %q: %v", p, err)
+		}
+		v.pattern = re
+		found = true
+	}
+	if o, ok := tag.Lookup("oneof"); ok {
+		v.oneof = strings.Split(o, ",")
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// isRangeValidatable returns true if min and max tags are applicable to t.
+func isRangeValidatable(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// validate validates s, a non-null value in CSV, and v, the value converted from s.
+// It returns the message describing the violated rule or an empty string if the value is valid.
+func (fv *fieldValidator) validate(s string, v reflect.Value) string {
+	if msg := fv.validateRange(v); msg != "" {
+		return msg
+	}
+	if fv.pattern != nil && !fv.pattern.MatchString(s) {
+		return fmt.Sprintf("must match %q", fv.pattern.String())
+	}
+	if fv.oneof != nil {
+		for _, o := range fv.oneof {
+			if s == o {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s", strings.Join(fv.oneof, ", "))
+	}
+	return ""
+}
+
+// validateRange validates v with min and max. The lengths of strings, slices and maps are validated.
+func (fv *fieldValidator) validateRange(v reflect.Value) string {
+	if fv.min == nil && fv.max == nil {
+		return ""
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	var x float64
+	subject := "must"
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		x = v.Float()
+	case reflect.String:
+		x = float64(utf8.RuneCountInString(v.String()))
+		subject = "length must"
+	default:
+		x = float64(v.Len())
+		subject = "length must"
+	}
+	if fv.min != nil && x < *fv.min {
+		return fmt.Sprintf("%s be at least %v", subject, *fv.min)
+	}
+	if fv.max != nil && x > *fv.max {
+		return fmt.Sprintf("%s be at most %v", subject, *fv.max)
+	}
+	return ""
+}
+
+// createValidatingConverter creates a converter which validates inputs converted with conv by fv.
+// The converter reports a *ValidationError for invalid values. Column and Line are filled by Reader.
+func createValidatingConverter(opt Option, conv interface{}, fv *fieldValidator, fieldName string) interface{} {
+	c := reflect.ValueOf(conv)
+	return reflect.MakeFunc(c.Type(), func(args []reflect.Value) []reflect.Value {
+		s := args[0].String()
+		fail := func(msg string) []reflect.Value {
+			err := &ValidationError{Field: fieldName, Value: s, Message: msg}
+			return []reflect.Value{reflect.Zero(c.Type().Out(0)), errorValue(err)}
+		}
+		null := opt.isNull(s)
+		if null && fv.required {
+			return fail("must not be empty")
+		}
+		rets := c.Call(args)
+		if !rets[1].IsNil() || null {
+			return rets
+		}
+		if msg := fv.validate(s, rets[0]); msg != "" {
+			return fail(msg)
+		}
+		return rets
+	}).Interface()
+}
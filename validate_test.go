@@ -0,0 +1,111 @@
+package easycsv
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validatedEntry struct {
+	Name   string   `name:"name" required:"true" max:"5"`
+	Age    int      `name:"age" min:"0" max:"150"`
+	Code   string   `name:"code" pattern:"^[A-Z]{2}$"`
+	Status string   `name:"status" oneof:"active,inactive"`
+	Score  *float64 `name:"score" min:"0.5"`
+}
+
+func TestValidation(t *testing.T) {
+	f := bytes.NewBufferString("name,age,code,status,score\nAlice,20,JP,active,\nBob,0,US,inactive,0.5\n")
+	var got []validatedEntry
+	if err := NewReader(f).ReadAll(&got); err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Unexpected number of rows: %d", len(got))
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	tests := []struct {
+		row string
+		err string
+	}{
+		{row: ",20,JP,active,1", err: `Invalid value "" for field Name (column name, line 3): must not be empty`},
+		{row: "Alexander,20,JP,active,1", err: `Invalid value "Alexander" for field Name (column name, line 3): length must be at most 5`},
+		{row: "Alice,-1,JP,active,1", err: `Invalid value "-1" for field Age (column age, line 3): must be at least 0`},
+		{row: "Alice,20,jp,active,1", err: `Invalid value "jp" for field Code (column code, line 3): must match "^[A-Z]{2}$"`},
+		{row: "Alice,20,JP,deleted,1", err: `Invalid value "deleted" for field Status (column status, line 3): must be one of active, inactive`},
+		{row: "Alice,20,JP,active,0.1", err: `Invalid value "0.1" for field Score (column score, line 3): must be at least 0.5`},
+	}
+	for _, test := range tests {
+		f := bytes.NewBufferString("name,age,code,status,score\nBob,30,US,active,1\n" + test.row)
+		var got []validatedEntry
+		err := NewReader(f).ReadAll(&got)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("Unexpected error for %q: %v", test.row, err)
+		}
+		if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("The error for %q is not ValidationError: %T", test.row, err)
+		}
+	}
+}
+
+func TestValidationRequiredInShortRow(t *testing.T) {
+	r := NewReader(bytes.NewBufferString("a,1\nb"), Option{FieldsPerRecord: -1})
+	type entry struct {
+		Name  string `index:"0"`
+		Count int    `index:"1" required:"true"`
+	}
+	var got []entry
+	err := r.ReadAll(&got)
+	if err == nil || err.Error() != `Invalid value "" for field Count (column 1, line 2): must not be empty` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestNewDecoder_InvalidValidationTags(t *testing.T) {
+	_, err := newDecoder(Option{}, reflect.TypeOf(struct {
+		A bool   `index:"0" min:"1"`
+		B int    `index:"1" max:"x"`
+		C string `index:"2" pattern:"[a"`
+		D int    `index:"3" min:"10" default:"5"`
+	}{}))
+	want := []string{
+		"Invalid validation tags of field A: min is not supported for bool",
+		"Invalid validation tags of field B: Failed to parse max \"x\"",
+		"Invalid validation tags of field C: Failed to parse pattern \"[a\": error parsing regexp: missing closing ]: `[a`",
+		"Failed to decode the default value of field D: must be at least 10",
+	}
+	if err == nil || err.Error() != strings.Join(want, "\n") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidationColumnGroup(t *testing.T) {
+	f := bytes.NewBufferString("name,s_1,s_2\nAlice,1,10\nBob,-5,1000\n")
+	var got []struct {
+		Name   string `name:"name"`
+		Scores []int  `match:"s_*" min:"0" max:"10"`
+	}
+	err := NewReader(f).ReadAll(&got)
+	if err == nil || err.Error() != `Invalid value "-5" for field Scores (column s_1, line 3): must be at least 0` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+type validatedLatLng struct {
+	Lat float64 `min:"-90" max:"90"`
+	Lng float64 `min:"-180" max:"180"`
+}
+
+func TestValidationCompoundStructField(t *testing.T) {
+	f := bytes.NewBufferString("35.6|139.7\n500|0\n")
+	var got []struct {
+		Pos validatedLatLng `index:"0" sep:"|"`
+	}
+	err := NewReader(f).ReadAll(&got)
+	if err == nil || err.Error() != `Invalid value "500" for field Pos.Lat (column 0, line 2): must be at most 90` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}